@@ -73,6 +73,11 @@ func main() {
 		}
 	}
 
+	// 嵌入网页资源
+	if cfg.WebDist != "" {
+		steps = append([]build.Step{{Name: "打包网页资源", Fn: b.PackWeb}}, steps...)
+	}
+
 	// 执行构建
 	fmt.Printf("开始构建: %s v%s\n", cfg.AppName, cfg.AppVersion)
 	fmt.Printf("项目目录: %s\n", projectRoot)
@@ -5,8 +5,16 @@ go 1.20
 require (
 	github.com/energye/systray v1.0.3
 	github.com/fsnotify/fsnotify v1.7.0
+	github.com/godbus/dbus/v5 v5.1.0
 	github.com/joho/godotenv v1.5.1
+	github.com/srwiley/oksvg v0.0.0-20221011165216-be6e8873101c
+	github.com/srwiley/rasterx v0.0.0-20220730225603-2ab79fcdd4ef
+	golang.org/x/crypto v0.17.0
+	golang.org/x/image v0.14.0
 	golang.org/x/sys v0.15.0
 )
 
-require github.com/godbus/dbus/v5 v5.1.0 // indirect
+require (
+	golang.org/x/net v0.10.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
@@ -17,6 +17,7 @@ type Config struct {
 	AppPublisher string
 	AppURL       string
 	OutputName   string
+	WebDist      string // 要嵌入程序的网页目录（绝对路径，空则不嵌入）
 }
 
 // LoadConfig 加载配置（支持 .env.local 覆盖）
@@ -61,6 +62,12 @@ func LoadConfig(envFile, projectRoot string) (*Config, error) {
 		AppPublisher: getVar(vars, "APP_PUBLISHER", "Vanisper"),
 		AppURL:       getVar(vars, "APP_URL", "https://github.com/vanisper/weblauncher"),
 		OutputName:   getVar(vars, "OUTPUT_NAME", "weblauncher.exe"),
+		WebDist:      vars["WEB_DIST"],
+	}
+
+	// 网页目录相对于项目根目录
+	if cfg.WebDist != "" && !filepath.IsAbs(cfg.WebDist) {
+		cfg.WebDist = filepath.Join(projectRoot, cfg.WebDist)
 	}
 
 	return cfg, nil
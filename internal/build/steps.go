@@ -119,7 +119,13 @@ func (b *Builder) BuildExe() error {
 	}
 
 	ldflags := "-s -w -H=windowsgui"
-	cmd := exec.Command("go", "build", "-ldflags", ldflags, "-o", outputPath, ".")
+	args := []string{"build", "-ldflags", ldflags}
+	if b.Config.WebDist != "" {
+		// 嵌入网页归档（需先执行 PackWeb）
+		args = append(args, "-tags", WebTag)
+	}
+	args = append(args, "-o", outputPath, ".")
+	cmd := exec.Command("go", args...)
 	cmd.Dir = srcDir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -165,6 +171,7 @@ func (b *Builder) Clean(clearOutput bool) error {
 	toRemove := []string{
 		filepath.Join(b.ProjectRoot, "src", "rsrc.syso"),
 		filepath.Join(b.ProjectRoot, "build", "installer", "setup.iss"),
+		b.webAssetsDir(),
 	}
 
 	if clearOutput {
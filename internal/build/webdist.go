@@ -0,0 +1,134 @@
+package build
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// WebTag 嵌入网页资源时使用的构建标签
+const WebTag = "webdist"
+
+// 已压缩的文件直接存储，不再重复压缩
+var storedExts = map[string]bool{
+	".br": true, ".gz": true, ".zip": true,
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".webp": true, ".avif": true,
+	".woff": true, ".woff2": true, ".mp3": true, ".mp4": true, ".webm": true,
+}
+
+// webAssetsDir 网页资源归档的输出目录（由 src 包通过 go:embed 嵌入）
+func (b *Builder) webAssetsDir() string {
+	return filepath.Join(b.ProjectRoot, "src", "assets", "web")
+}
+
+// PackWeb 将 WEB_DIST 目录打包为 zip 归档，文件名为内容哈希
+func (b *Builder) PackWeb() error {
+	dist := b.Config.WebDist
+	if info, err := os.Stat(dist); err != nil || !info.IsDir() {
+		return fmt.Errorf("网页目录不存在: %s", dist)
+	}
+	if _, err := os.Stat(filepath.Join(dist, "index.html")); err != nil {
+		return fmt.Errorf("网页目录中缺少 index.html: %s", dist)
+	}
+
+	// 收集文件并排序，保证相同内容得到相同的哈希
+	var files []string
+	err := filepath.Walk(dist, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			rel, err := filepath.Rel(dist, path)
+			if err != nil {
+				return err
+			}
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("遍历网页目录失败: %w", err)
+	}
+	sort.Strings(files)
+
+	outDir := b.webAssetsDir()
+	if err := os.RemoveAll(outDir); err != nil {
+		return fmt.Errorf("清理旧的网页归档失败: %w", err)
+	}
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return fmt.Errorf("创建网页归档目录失败: %w", err)
+	}
+
+	tmpPath := filepath.Join(outDir, "web.zip.tmp")
+	out, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("创建网页归档失败: %w", err)
+	}
+
+	hash := sha256.New()
+	zw := zip.NewWriter(out)
+	for _, name := range files {
+		if err := addZipFile(zw, hash, dist, name); err != nil {
+			zw.Close()
+			out.Close()
+			os.Remove(tmpPath)
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		out.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("写入网页归档失败: %w", err)
+	}
+	out.Close()
+
+	sum := hex.EncodeToString(hash.Sum(nil))[:16]
+	finalPath := filepath.Join(outDir, sum+".zip")
+	if err := os.Rename(tmpPath, finalPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("重命名网页归档失败: %w", err)
+	}
+	fmt.Printf("  已打包 %d 个文件: %s\n", len(files), finalPath)
+	return nil
+}
+
+// addZipFile 写入单个文件，同时将路径和内容计入哈希
+func addZipFile(zw *zip.Writer, hash io.Writer, dist, name string) error {
+	path := filepath.Join(dist, filepath.FromSlash(name))
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	header.Method = zip.Deflate
+	if storedExts[strings.ToLower(filepath.Ext(name))] {
+		header.Method = zip.Store
+	}
+
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return fmt.Errorf("写入 %s 失败: %w", name, err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fmt.Fprintf(hash, "%s\x00%d\x00", name, info.Size())
+	if _, err := io.Copy(io.MultiWriter(w, hash), f); err != nil {
+		return fmt.Errorf("写入 %s 失败: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+
+	"github.com/energye/systray"
+)
+
+// 自定义菜单项类型
+const (
+	ActionURL       = "url"       // 打开网页
+	ActionFile      = "file"      // 打开本地文件或文件夹
+	ActionCommand   = "command"   // 执行命令
+	ActionCopy      = "copy"      // 复制文本到剪贴板
+	ActionForm      = "form"      // 自动提交表单
+	ActionSeparator = "separator" // 分隔线
+	ActionSubmenu   = "submenu"   // 子菜单
+)
+
+// MenuEntry 配置中声明的自定义托盘菜单项
+type MenuEntry struct {
+	Type    string `json:"type"`
+	Title   string `json:"title,omitempty"`
+	Tooltip string `json:"tooltip,omitempty"`
+
+	URL     string         `json:"url,omitempty"`     // url：网页地址
+	Browser *BrowserConfig `json:"browser,omitempty"` // url：指定浏览器
+	Path    string         `json:"path,omitempty"`    // file：文件或文件夹路径
+	Command string         `json:"command,omitempty"` // command：可执行文件（shell 为 true 时为完整命令行）
+	Args    []string       `json:"args,omitempty"`    // command：参数
+	Dir     string         `json:"dir,omitempty"`     // command：工作目录
+	Shell   bool           `json:"shell,omitempty"`   // command：通过系统 shell 执行（默认不使用 shell）
+	Text    string         `json:"text,omitempty"`    // copy：要复制的文本
+	Form    *FormConfig    `json:"form,omitempty"`    // form：表单（browser 同样适用）
+
+	Items []MenuEntry `json:"items,omitempty"` // submenu：子菜单项
+}
+
+// addMenuEntries 将自定义菜单项添加到托盘菜单，parent 为 nil 时添加到顶层
+func addMenuEntries(parent *systray.MenuItem, entries []MenuEntry) {
+	for _, e := range entries {
+		e := e
+		if e.Type == ActionSeparator {
+			if parent == nil {
+				systray.AddSeparator()
+			} else {
+				// systray 不支持子菜单分隔线，使用禁用的菜单项代替
+				parent.AddSubMenuItem("──────", "").Disable()
+			}
+			continue
+		}
+
+		var item *systray.MenuItem
+		if parent == nil {
+			item = systray.AddMenuItem(e.Title, e.Tooltip)
+		} else {
+			item = parent.AddSubMenuItem(e.Title, e.Tooltip)
+		}
+
+		if e.Type == ActionSubmenu || len(e.Items) > 0 {
+			addMenuEntries(item, e.Items)
+			continue
+		}
+		item.Click(func() {
+			if err := runAction(e); err != nil {
+				reportActionError(e.Title, err)
+				return
+			}
+			setTrayStatus("action", "")
+		})
+	}
+}
+
+// runAction 执行菜单项动作
+func runAction(e MenuEntry) error {
+	switch e.Type {
+	case ActionURL:
+		return openURLWith(e.URL, e.Browser)
+	case ActionFile:
+		// 交给系统默认程序打开（文件夹使用文件管理器），相对路径基于配置文件所在目录
+		return openBrowser(config.ResolvePath(e.Path))
+	case ActionCommand:
+		return runCommand(e)
+	case ActionCopy:
+		return copyToClipboard(e.Text)
+	case ActionForm:
+		if e.Form == nil {
+			return errors.New(T("err.form_empty"))
+		}
+		return openForm(e.Title, e.Form, e.Browser)
+	default:
+		return fmt.Errorf(T("err.action_unknown"), e.Type)
+	}
+}
+
+// runCommand 异步执行命令，输出写入日志
+func runCommand(e MenuEntry) error {
+	if e.Command == "" {
+		return errors.New(T("err.command_empty"))
+	}
+
+	var cmd *exec.Cmd
+	if e.Shell {
+		// command 为命令行，args 逐个引用后追加，避免被 shell 拆分或解释
+		line := e.Command
+		for _, a := range e.Args {
+			line += " " + shellQuote(a)
+		}
+		cmd = shellCommand(line)
+	} else {
+		cmd = exec.Command(e.Command, e.Args...)
+	}
+	cmd.Dir = config.ResolvePath(e.Dir)
+	hideWindow(cmd)
+
+	out := newLogWriter(e.Title)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	log.Printf(T("log.command_started"), e.Title, strings.Join(cmd.Args, " "))
+
+	go func() {
+		err := cmd.Wait()
+		out.Flush()
+		if err != nil {
+			reportActionError(e.Title, err)
+			return
+		}
+		log.Printf(T("log.command_done"), e.Title)
+	}()
+	return nil
+}
+
+// reportActionError 菜单项执行失败：写入日志，在托盘提示中显示并发送通知
+func reportActionError(title string, err error) {
+	log.Printf(T("log.action_failed"), title, err)
+	setTrayStatus("action", T("tray.action_failed", title, err))
+	notify(Notification{
+		Tag:     "action",
+		Title:   T("notify.action_error", title),
+		Body:    err.Error(),
+		Urgency: urgencyNormal,
+	})
+}
@@ -0,0 +1,14 @@
+//go:build linux
+
+package main
+
+import "os/exec"
+
+func openBrowser(url string) error {
+	cmd := exec.Command("xdg-open", url)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
@@ -0,0 +1,22 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// installCA 将本地 CA 加入登录钥匙串并设为受信任（系统会要求输入密码确认）
+func installCA(caPath string) ([]string, error) {
+	home, _ := os.UserHomeDir()
+	keychain := filepath.Join(home, "Library", "Keychains", "login.keychain-db")
+	out, err := exec.Command("security", "add-trusted-cert", "-r", "trustRoot", "-k", keychain, caPath).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("security: %v %s", err, strings.TrimSpace(string(out)))
+	}
+	return []string{keychain}, nil
+}
@@ -0,0 +1,92 @@
+//go:build linux
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const caNickname = "WebLauncher Local CA"
+
+// installCA 将本地 CA 加入 NSS 数据库（Chrome / Chromium 和各 Firefox 配置），
+// 以 root 运行时同时加入系统信任列表，返回已安装的位置
+func installCA(caPath string) ([]string, error) {
+	var installed []string
+	var errs []string
+
+	home, _ := os.UserHomeDir()
+	dbs := []string{filepath.Join(home, ".pki", "nssdb")}
+	for _, pattern := range []string{
+		filepath.Join(home, ".mozilla", "firefox", "*"),
+		filepath.Join(home, "snap", "firefox", "common", ".mozilla", "firefox", "*"),
+	} {
+		profiles, _ := filepath.Glob(pattern)
+		for _, p := range profiles {
+			if _, err := os.Stat(filepath.Join(p, "cert9.db")); err == nil {
+				dbs = append(dbs, p)
+			}
+		}
+	}
+
+	if _, err := exec.LookPath("certutil"); err != nil {
+		errs = append(errs, T("err.cert_no_certutil"))
+	} else {
+		for _, db := range dbs {
+			if err := os.MkdirAll(db, 0700); err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			// 先删除同名旧证书（CA 重新生成后需要替换），忽略不存在的错误
+			exec.Command("certutil", "-d", "sql:"+db, "-D", "-n", caNickname).Run()
+			out, err := exec.Command("certutil", "-d", "sql:"+db, "-A", "-t", "C,,", "-n", caNickname, "-i", caPath).CombinedOutput()
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v %s", db, err, strings.TrimSpace(string(out))))
+				continue
+			}
+			installed = append(installed, db)
+		}
+	}
+
+	// 系统信任列表需要 root 权限
+	if os.Geteuid() == 0 {
+		if err := installSystemCA(caPath); err != nil {
+			errs = append(errs, err.Error())
+		} else {
+			installed = append(installed, T("cert.system_store"))
+		}
+	}
+
+	if len(errs) > 0 {
+		return installed, errors.New(strings.Join(errs, "\n"))
+	}
+	return installed, nil
+}
+
+// installSystemCA 加入系统信任列表（Debian / Ubuntu、Fedora / RHEL）
+func installSystemCA(caPath string) error {
+	data, err := os.ReadFile(caPath)
+	if err != nil {
+		return err
+	}
+	for _, target := range []struct{ dir, cmd string }{
+		{"/usr/local/share/ca-certificates", "update-ca-certificates"},
+		{"/etc/pki/ca-trust/source/anchors", "update-ca-trust"},
+	} {
+		if _, err := os.Stat(target.dir); err != nil {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(target.dir, "weblauncher-local-ca.crt"), data, 0644); err != nil {
+			return err
+		}
+		if out, err := exec.Command(target.cmd).CombinedOutput(); err != nil {
+			return fmt.Errorf("%s: %v %s", target.cmd, err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+	return errors.New(T("err.cert_no_system_store"))
+}
@@ -0,0 +1,20 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// installCA 将本地 CA 加入当前用户的受信任根证书（系统会弹窗确认）
+func installCA(caPath string) ([]string, error) {
+	cmd := exec.Command("certutil", "-user", "-addstore", "Root", caPath)
+	hideWindow(cmd)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("certutil: %v %s", err, strings.TrimSpace(string(out)))
+	}
+	return []string{T("cert.user_root_store")}, nil
+}
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TLSConfig 本地服务使用 HTTPS：自动生成本地 CA 和服务器证书，保存在数据目录的 certs 下
+type TLSConfig struct {
+	Names []string `json:"names,omitempty"` // 证书包含的域名，默认 localhost；第一个用于打开网页（其他名称需解析到 127.0.0.1）
+}
+
+const (
+	caValidity      = 10 * 365 * 24 * time.Hour
+	leafValidity    = 397 * 24 * time.Hour // 浏览器接受的服务器证书最长有效期
+	leafRenewBefore = 30 * 24 * time.Hour  // 到期前多久重新签发
+)
+
+// localCerts 本地 CA 和服务器证书，到期前自动重新签发
+type localCerts struct {
+	dir   string
+	names []string
+
+	mu     sync.Mutex
+	ca     *x509.Certificate
+	caKey  crypto.Signer
+	leaf   *tls.Certificate
+	expiry time.Time
+}
+
+// certsDir 证书保存目录（静态配置模式没有数据目录，使用用户配置目录）
+func certsDir() string {
+	if DataDir == "" {
+		if dir, err := os.UserConfigDir(); err == nil {
+			return filepath.Join(dir, "WebLauncher", "certs")
+		}
+	}
+	return filepath.Join(DataDir, "certs")
+}
+
+// loadLocalCerts 读取或生成 CA 和服务器证书
+func loadLocalCerts(dir string, names []string) (*localCerts, error) {
+	if len(names) == 0 {
+		names = []string{"localhost"}
+	}
+	c := &localCerts{dir: dir, names: names}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf(T("err.cert_dir"), err)
+	}
+	if err := c.loadCA(); err != nil {
+		return nil, err
+	}
+	if err := c.loadLeaf(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Host 打开网页时使用的主机名
+func (c *localCerts) Host() string {
+	return c.names[0]
+}
+
+// CAPath 本地 CA 证书路径
+func (c *localCerts) CAPath() string {
+	return filepath.Join(c.dir, "ca.pem")
+}
+
+// GetCertificate 供 tls.Config 使用，证书即将到期时重新签发
+func (c *localCerts) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if time.Until(c.expiry) < leafRenewBefore {
+		if err := c.issueLeafLocked(); err != nil {
+			log.Printf(T("log.cert_renew_failed"), err)
+		}
+	}
+	return c.leaf, nil
+}
+
+// caPermittedDomains CA 名称约束允许的域名：证书包含的域名（通配符取其父域）和 localhost
+func caPermittedDomains(names []string) []string {
+	seen := map[string]bool{"localhost": true}
+	domains := []string{"localhost"}
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimPrefix(name, "*."))
+		if name != "" && !seen[name] {
+			seen[name] = true
+			domains = append(domains, name)
+		}
+	}
+	sort.Strings(domains)
+	return domains
+}
+
+// loadCA 读取 CA，不存在、已过期或名称约束与当前域名不符时重新生成（需要重新安装到信任列表）
+// CA 带有关键的名称约束，只能为本机回环地址和配置的域名签发证书，私钥泄露时也无法用于其他网站
+func (c *localCerts) loadCA() error {
+	certPath, keyPath := c.CAPath(), filepath.Join(c.dir, "ca-key.pem")
+	domains := caPermittedDomains(c.names)
+	if cert, key, err := readCertPair(certPath, keyPath); err == nil && time.Now().Before(cert.NotAfter) &&
+		cert.PermittedDNSDomainsCritical && reflect.DeepEqual(cert.PermittedDNSDomains, domains) {
+		c.ca, c.caKey = cert, key
+		return nil
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	hostname, _ := os.Hostname()
+	tmpl := &x509.Certificate{
+		SerialNumber:          randomSerial(),
+		Subject:               pkix.Name{CommonName: "WebLauncher Local CA (" + hostname + ")", Organization: []string{"WebLauncher"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+
+		PermittedDNSDomainsCritical: true,
+		PermittedDNSDomains:         domains,
+		PermittedIPRanges: []*net.IPNet{
+			{IP: net.IPv4(127, 0, 0, 0).To4(), Mask: net.CIDRMask(8, 32)},
+			{IP: net.IPv6loopback, Mask: net.CIDRMask(128, 128)},
+		},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		return err
+	}
+	cert, _ := x509.ParseCertificate(der)
+	if err := writeCertPair(certPath, keyPath, der, key); err != nil {
+		return err
+	}
+	log.Printf(T("log.cert_ca_created"), certPath)
+	c.ca, c.caKey = cert, key
+	return nil
+}
+
+// loadLeaf 读取服务器证书，名称变化、由其他 CA 签发或即将到期时重新签发
+func (c *localCerts) loadLeaf() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	certPath, keyPath := filepath.Join(c.dir, "cert.pem"), filepath.Join(c.dir, "key.pem")
+	cert, key, err := readCertPair(certPath, keyPath)
+	if err == nil && reflect.DeepEqual(cert.DNSNames, c.names) &&
+		cert.CheckSignatureFrom(c.ca) == nil && time.Until(cert.NotAfter) > leafRenewBefore {
+		der, _ := os.ReadFile(certPath)
+		block, _ := pem.Decode(der)
+		c.leaf = &tls.Certificate{Certificate: [][]byte{block.Bytes, c.ca.Raw}, PrivateKey: key, Leaf: cert}
+		c.expiry = cert.NotAfter
+		return nil
+	}
+	return c.issueLeafLocked()
+}
+
+// issueLeafLocked 签发服务器证书（调用方负责加锁）
+func (c *localCerts) issueLeafLocked() error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	notAfter := time.Now().Add(leafValidity)
+	if notAfter.After(c.ca.NotAfter) {
+		notAfter = c.ca.NotAfter
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: randomSerial(),
+		Subject:      pkix.Name{CommonName: c.names[0], Organization: []string{"WebLauncher"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     c.names,
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, c.ca, key.Public(), c.caKey)
+	if err != nil {
+		return err
+	}
+	cert, _ := x509.ParseCertificate(der)
+	if err := writeCertPair(filepath.Join(c.dir, "cert.pem"), filepath.Join(c.dir, "key.pem"), der, key); err != nil {
+		return err
+	}
+	log.Printf(T("log.cert_issued"), c.names, notAfter.Format("2006-01-02"))
+	c.leaf = &tls.Certificate{Certificate: [][]byte{der, c.ca.Raw}, PrivateKey: key, Leaf: cert}
+	c.expiry = notAfter
+	return nil
+}
+
+func randomSerial() *big.Int {
+	n, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	return n
+}
+
+// readCertPair 读取 PEM 格式的证书和私钥
+func readCertPair(certPath, keyPath string) (*x509.Certificate, crypto.Signer, error) {
+	certPEM, err := os.ReadFile(certPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	certBlock, _ := pem.Decode(certPEM)
+	keyBlock, _ := pem.Decode(keyPEM)
+	if certBlock == nil || keyBlock == nil {
+		return nil, nil, errors.New(T("err.cert_invalid"))
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, nil, errors.New(T("err.cert_invalid"))
+	}
+	return cert, signer, nil
+}
+
+// writeCertPair 保存证书和私钥（私钥仅当前用户可读）
+func writeCertPair(certPath, keyPath string, der []byte, key crypto.PrivateKey) error {
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		return fmt.Errorf(T("err.cert_write"), keyPath, err)
+	}
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		return fmt.Errorf(T("err.cert_write"), certPath, err)
+	}
+	return nil
+}
+
+var (
+	tlsMu      sync.Mutex
+	tlsApplied *TLSConfig // 当前生效的 HTTPS 配置
+)
+
+// applyTLS 按配置切换本地服务的 HTTPS（启动和热重载时调用，需在静态站点和代理之前）
+func applyTLS(cfg *TLSConfig) error {
+	tlsMu.Lock()
+	defer tlsMu.Unlock()
+	if reflect.DeepEqual(cfg, tlsApplied) {
+		return nil
+	}
+	if cfg == nil {
+		tlsApplied = nil
+		trustLocalCA(nil)
+		return localServer.SetTLS(nil)
+	}
+
+	certs, err := loadLocalCerts(certsDir(), cfg.Names)
+	if err != nil {
+		return err
+	}
+	applied := *cfg
+	tlsApplied = &applied
+	trustLocalCA(certs.ca)
+	return localServer.SetTLS(certs)
+}
+
+// localTrustTransport 就绪探测和健康检查使用的传输层，本地服务启用 HTTPS 时改用信任本地 CA 的传输层
+// 热重载时只替换内部的传输层，客户端本身不变，避免与进行中的请求竞争
+type localTrustTransport struct {
+	mu sync.Mutex
+	t  *http.Transport // nil 时使用 http.DefaultTransport
+}
+
+var localTrust = &localTrustTransport{}
+
+func (l *localTrustTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	l.mu.Lock()
+	t := l.t
+	l.mu.Unlock()
+	if t == nil {
+		return http.DefaultTransport.RoundTrip(r)
+	}
+	return t.RoundTrip(r)
+}
+
+// trustLocalCA 让就绪探测和健康检查信任本地 CA（本地服务启用 HTTPS 时），ca 为 nil 时恢复默认
+func trustLocalCA(ca *x509.Certificate) {
+	var transport *http.Transport
+	if ca != nil {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		pool.AddCert(ca)
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+	localTrust.mu.Lock()
+	old := localTrust.t
+	localTrust.t = transport
+	localTrust.mu.Unlock()
+	if old != nil {
+		old.CloseIdleConnections()
+	}
+}
+
+// runCertCommand 处理 cert 子命令，返回退出码
+//
+//	weblauncher cert install  生成（如尚未生成）本地 CA 并加入系统或浏览器的信任列表
+func runCertCommand(args []string) int {
+	if len(args) == 0 || args[0] != "install" {
+		fmt.Println(T("console.cert_usage"))
+		return 2
+	}
+
+	var err error
+	config, err = LoadConfig(*staticConfig)
+	if err != nil {
+		fmt.Println(T("console.load_config_failed", err))
+		return 1
+	}
+	var names []string
+	if cfg := config.GetTLS(); cfg != nil {
+		names = cfg.Names
+	}
+	certs, err := loadLocalCerts(certsDir(), names)
+	if err != nil {
+		fmt.Println(T("console.cert_failed", err))
+		return 1
+	}
+
+	fmt.Println(T("console.cert_ca", certs.CAPath()))
+	installed, err := installCA(certs.CAPath())
+	for _, place := range installed {
+		fmt.Println(T("console.cert_installed", place))
+	}
+	if err != nil {
+		fmt.Println(T("console.cert_install_failed", err))
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,19 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// copyToClipboard 复制文本到剪贴板
+func copyToClipboard(text string) error {
+	cmd := exec.Command("pbcopy")
+	cmd.Stdin = strings.NewReader(text)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf(T("err.clipboard_tool"), "pbcopy", fmt.Errorf("%w: %s", err, out))
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+//go:build linux
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// copyToClipboard 复制文本到剪贴板（依次尝试 wl-copy / xclip / xsel）
+func copyToClipboard(text string) error {
+	var candidates [][]string
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		candidates = append(candidates, []string{"wl-copy"})
+	}
+	candidates = append(candidates,
+		[]string{"xclip", "-selection", "clipboard"},
+		[]string{"xsel", "--clipboard", "--input"},
+	)
+
+	for _, args := range candidates {
+		path, err := exec.LookPath(args[0])
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		// xclip 会常驻后台持有剪贴板，不能等待其输出管道关闭
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf(T("err.clipboard_tool"), args[0], err)
+		}
+		return nil
+	}
+	return errors.New(T("err.clipboard_no_tool"))
+}
@@ -0,0 +1,82 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	user32               = windows.NewLazySystemDLL("user32.dll")
+	kernel32             = windows.NewLazySystemDLL("kernel32.dll")
+	procOpenClipboard    = user32.NewProc("OpenClipboard")
+	procCloseClipboard   = user32.NewProc("CloseClipboard")
+	procEmptyClipboard   = user32.NewProc("EmptyClipboard")
+	procSetClipboardData = user32.NewProc("SetClipboardData")
+	procGlobalAlloc      = kernel32.NewProc("GlobalAlloc")
+	procGlobalFree       = kernel32.NewProc("GlobalFree")
+	procGlobalLock       = kernel32.NewProc("GlobalLock")
+	procGlobalUnlock     = kernel32.NewProc("GlobalUnlock")
+	procRtlMoveMemory    = kernel32.NewProc("RtlMoveMemory")
+)
+
+const (
+	cfUnicodeText = 13
+	gmemMoveable  = 0x0002
+)
+
+// copyToClipboard 复制文本到剪贴板（CF_UNICODETEXT）
+func copyToClipboard(text string) error {
+	// 剪贴板归属于调用线程，整个过程需固定在同一线程
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	data, err := windows.UTF16FromString(text)
+	if err != nil {
+		return err
+	}
+
+	// 剪贴板可能被其他程序占用，短暂重试
+	opened := false
+	for i := 0; i < 10; i++ {
+		if r, _, _ := procOpenClipboard.Call(0); r != 0 {
+			opened = true
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if !opened {
+		return errors.New(T("err.clipboard_open"))
+	}
+	defer procCloseClipboard.Call()
+
+	if r, _, err := procEmptyClipboard.Call(); r == 0 {
+		return fmt.Errorf(T("err.clipboard_empty"), err)
+	}
+
+	size := uintptr(len(data) * 2)
+	h, _, err := procGlobalAlloc.Call(gmemMoveable, size)
+	if h == 0 {
+		return fmt.Errorf(T("err.clipboard_alloc"), err)
+	}
+	p, _, err := procGlobalLock.Call(h)
+	if p == 0 {
+		procGlobalFree.Call(h)
+		return fmt.Errorf(T("err.clipboard_lock"), err)
+	}
+	procRtlMoveMemory.Call(p, uintptr(unsafe.Pointer(&data[0])), size)
+	procGlobalUnlock.Call(h)
+
+	// 成功后内存归系统所有，不能再释放
+	if r, _, err := procSetClipboardData.Call(cfUnicodeText, h); r == 0 {
+		procGlobalFree.Call(h)
+		return fmt.Errorf(T("err.clipboard_write"), err)
+	}
+	return nil
+}
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"bytes"
 	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -25,7 +27,7 @@ var DataDir string
 func DetermineDataDir() error {
 	exe, err := os.Executable()
 	if err != nil {
-		return fmt.Errorf("无法获取可执行程序路径: %w", err)
+		return fmt.Errorf(T("err.exe_path"), err)
 	}
 
 	exeDir := filepath.Dir(exe)
@@ -50,7 +52,7 @@ func DetermineDataDir() error {
 		return nil
 	}
 
-	return fmt.Errorf("无法确定数据目录：所有位置都无法写入")
+	return errors.New(T("err.no_data_dir"))
 }
 
 // testAndCreateDir 测试目录是否可以写入，如果不存在则创建
@@ -80,6 +82,30 @@ type Config struct {
 	Icon      string `json:"icon"` // 外置图标路径（空则使用内嵌）
 	AutoStart bool   `json:"autoStart"`
 	TrayMode  bool   `json:"trayMode"`
+	Sites     []Site `json:"sites,omitempty"` // 多站点列表（托盘子菜单）
+
+	Browser       *BrowserConfig   `json:"browser,omitempty"`       // 指定浏览器（空则使用系统默认浏览器）
+	Profiles      []Profile        `json:"profiles,omitempty"`      // 环境配置（dev / staging / prod 等）
+	ActiveProfile string           `json:"activeProfile,omitempty"` // 当前启用的环境
+	Menu          []MenuEntry      `json:"menu,omitempty"`          // 自定义托盘菜单项
+	Language      string           `json:"language,omitempty"`      // 界面语言（如 zh-CN、en，空则跟随系统）
+	StaticSite    *StaticConfig    `json:"static,omitempty"`        // 静态站点模式（与命令行 -static 无关）
+	Sidecar       *SidecarConfig   `json:"sidecar,omitempty"`       // 随程序启动的本地后端进程
+	Wait          *WaitConfig      `json:"wait,omitempty"`          // 打开网页前等待目标地址可访问
+	Health        *HealthConfig    `json:"health,omitempty"`        // 后台健康检查
+	Mirrors       *MirrorConfig    `json:"mirrors,omitempty"`       // 备用地址
+	NetworkRules  []NetworkRule    `json:"networkRules,omitempty"`  // 按网络环境选择地址
+	Offline       *OfflineConfig   `json:"offline,omitempty"`       // 目标地址不可访问时打开的离线页面
+	Proxy         *ProxyConfig     `json:"proxy,omitempty"`         // 本地认证代理
+	TLS           *TLSConfig       `json:"tls,omitempty"`           // 本地服务使用 HTTPS
+	Tunnel        *TunnelConfig    `json:"tunnel,omitempty"`        // 通过 SSH 隧道访问
+	WakeOnLan     *WakeOnLanConfig `json:"wakeOnLan,omitempty"`     // 打开网页前发送网络唤醒魔术包
+	Scheme        *SchemeConfig    `json:"scheme,omitempty"`        // 自定义协议链接
+	Router        *RouterConfig    `json:"router,omitempty"`        // 路由模式（作为默认浏览器按规则分发链接）
+	StartupURLs   []StartupURL     `json:"startupUrls,omitempty"`   // 托盘启动时依次打开的网页（代替 url）
+	Schedules     []Schedule       `json:"schedules,omitempty"`     // 定时打开网页或执行动作
+	Kiosk         *KioskConfig     `json:"kiosk,omitempty"`         // 看板模式：浏览器关闭后自动重新打开
+	Notifications *bool            `json:"notifications,omitempty"` // 是否显示桌面通知，默认 true
 
 	mu       sync.RWMutex `json:"-"`
 	saving   bool         `json:"-"` // 防止自循环标记
@@ -110,7 +136,7 @@ func LoadConfig(isStatic bool) (*Config, error) {
 
 	// 确定全局数据目录（exe 旁边 > APPDATA > TEMP）
 	if err := DetermineDataDir(); err != nil {
-		fmt.Fprintf(os.Stderr, "致命错误：无法确定数据目录: %v\n", err)
+		fmt.Fprintln(os.Stderr, T("console.data_dir_fatal", err))
 		os.Exit(1)
 	}
 
@@ -120,7 +146,7 @@ func LoadConfig(isStatic bool) (*Config, error) {
 	}
 	// 确保数据目录存在
 	if err := os.MkdirAll(DataDir, 0755); err != nil {
-		return nil, fmt.Errorf("无法创建数据目录: %w", err)
+		return nil, fmt.Errorf(T("err.create_data_dir"), err)
 	}
 	// 使用全局数据目录
 	c.dir = DataDir
@@ -131,17 +157,7 @@ func LoadConfig(isStatic bool) (*Config, error) {
 		data, _ := os.ReadFile(c.path)
 		var ext Config
 		if err := json.Unmarshal(data, &ext); err == nil {
-			if ext.Title != "" {
-				c.Title = ext.Title
-			}
-			if ext.URL != "" {
-				c.URL = ext.URL
-			}
-			if ext.Icon != "" {
-				c.Icon = ext.Icon
-			}
-			c.AutoStart = ext.AutoStart
-			c.TrayMode = ext.TrayMode
+			c.merge(&ext)
 		}
 	} else {
 		// 不存在则创建默认外置配置
@@ -151,6 +167,44 @@ func LoadConfig(isStatic bool) (*Config, error) {
 	return c, nil
 }
 
+// merge 使用外置配置覆盖当前配置（调用方负责加锁）
+func (c *Config) merge(ext *Config) {
+	if ext.Title != "" {
+		c.Title = ext.Title
+	}
+	if ext.URL != "" {
+		c.URL = ext.URL
+	}
+	if ext.Icon != "" {
+		c.Icon = ext.Icon
+	}
+	c.AutoStart = ext.AutoStart
+	c.TrayMode = ext.TrayMode
+	c.Sites = ext.Sites
+	c.Browser = ext.Browser
+	c.Profiles = ext.Profiles
+	c.ActiveProfile = ext.ActiveProfile
+	c.Menu = ext.Menu
+	c.Language = ext.Language
+	c.StaticSite = ext.StaticSite
+	c.Sidecar = ext.Sidecar
+	c.Wait = ext.Wait
+	c.Health = ext.Health
+	c.Mirrors = ext.Mirrors
+	c.NetworkRules = ext.NetworkRules
+	c.Offline = ext.Offline
+	c.Proxy = ext.Proxy
+	c.TLS = ext.TLS
+	c.Tunnel = ext.Tunnel
+	c.WakeOnLan = ext.WakeOnLan
+	c.Scheme = ext.Scheme
+	c.Router = ext.Router
+	c.StartupURLs = ext.StartupURLs
+	c.Schedules = ext.Schedules
+	c.Kiosk = ext.Kiosk
+	c.Notifications = ext.Notifications
+}
+
 func (c *Config) SetStatic(val bool) {
 	c.mu.Lock()
 	c.Static = val
@@ -168,16 +222,323 @@ func (c *Config) GetTitle() string {
 	return c.Title
 }
 
+// GetURL 返回当前环境下的 URL
 func (c *Config) GetURL() string {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
+	if p := c.activeProfile(); p != nil && p.URL != "" {
+		return p.URL
+	}
 	return c.URL
 }
 
+// GetIcon 返回当前环境下的图标路径（相对路径基于配置文件所在目录）
 func (c *Config) GetIcon() string {
+	c.mu.RLock()
+	icon := c.Icon
+	if p := c.activeProfile(); p != nil && p.Icon != "" {
+		icon = p.Icon
+	}
+	c.mu.RUnlock()
+	return c.ResolvePath(icon)
+}
+
+// ResolvePath 将相对路径解析为基于配置文件所在目录的绝对路径
+func (c *Config) ResolvePath(path string) string {
+	if path == "" || filepath.IsAbs(path) || c.dir == "" {
+		return path
+	}
+	return filepath.Join(c.dir, path)
+}
+
+// GetBrowser 返回当前环境下的浏览器配置（nil 表示系统默认浏览器）
+func (c *Config) GetBrowser() *BrowserConfig {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if p := c.activeProfile(); p != nil && p.Browser != nil {
+		return p.Browser
+	}
+	return c.Browser
+}
+
+// GetTrayTitle 返回托盘显示的标题，带上当前环境名称以免误操作生产环境
+func (c *Config) GetTrayTitle() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	p := c.activeProfile()
+	if p == nil {
+		return c.Title
+	}
+	title := c.Title
+	if p.Title != "" {
+		title = p.Title
+	}
+	return fmt.Sprintf("%s [%s]", title, p.Name)
+}
+
+// GetProfiles 返回环境列表的副本
+func (c *Config) GetProfiles() []Profile {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	profiles := make([]Profile, len(c.Profiles))
+	copy(profiles, c.Profiles)
+	return profiles
+}
+
+// GetActiveProfile 返回当前启用的环境名称（未启用或不存在时返回空）
+func (c *Config) GetActiveProfile() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if p := c.activeProfile(); p != nil {
+		return p.Name
+	}
+	return ""
+}
+
+// SetActiveProfile 切换环境并保存，name 为空表示不使用环境
+func (c *Config) SetActiveProfile(name string) error {
+	c.mu.Lock()
+	if name != "" && c.findProfile(name) == nil {
+		c.mu.Unlock()
+		return fmt.Errorf(T("err.profile_not_found"), name)
+	}
+	c.ActiveProfile = name
+	c.mu.Unlock()
+	c.Save()
+	return nil
+}
+
+// activeProfile 返回当前启用的环境（调用方负责加锁）
+func (c *Config) activeProfile() *Profile {
+	if c.ActiveProfile == "" {
+		return nil
+	}
+	return c.findProfile(c.ActiveProfile)
+}
+
+// findProfile 按名称查找环境（调用方负责加锁）
+func (c *Config) findProfile(name string) *Profile {
+	for i := range c.Profiles {
+		if c.Profiles[i].Name == name {
+			return &c.Profiles[i]
+		}
+	}
+	return nil
+}
+
+// GetSites 返回站点列表的副本
+func (c *Config) GetSites() []Site {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	sites := make([]Site, len(c.Sites))
+	copy(sites, c.Sites)
+	return sites
+}
+
+// GetLanguage 返回配置的界面语言（空表示跟随系统）
+func (c *Config) GetLanguage() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Language
+}
+
+// GetStatic 返回静态站点配置（未启用时为 nil）
+func (c *Config) GetStatic() *StaticConfig {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.StaticSite == nil {
+		return nil
+	}
+	s := *c.StaticSite
+	return &s
+}
+
+// GetSidecar 返回后端进程配置（未配置时为 nil）
+func (c *Config) GetSidecar() *SidecarConfig {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	return c.Icon
+	if c.Sidecar == nil {
+		return nil
+	}
+	s := *c.Sidecar
+	return &s
+}
+
+// GetWait 返回就绪等待配置（未配置时为 nil）
+func (c *Config) GetWait() *WaitConfig {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.Wait == nil {
+		return nil
+	}
+	w := *c.Wait
+	return &w
+}
+
+// GetHealth 返回健康检查配置（未配置时为 nil）
+func (c *Config) GetHealth() *HealthConfig {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.Health == nil {
+		return nil
+	}
+	h := *c.Health
+	h.ExpectStatus = append([]int(nil), h.ExpectStatus...)
+	return &h
+}
+
+// GetMirrors 返回备用地址配置（未配置时为 nil）
+func (c *Config) GetMirrors() *MirrorConfig {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.Mirrors == nil {
+		return nil
+	}
+	m := *c.Mirrors
+	m.URLs = append([]string(nil), m.URLs...)
+	return &m
+}
+
+// GetNetworkRules 返回网络规则列表
+func (c *Config) GetNetworkRules() []NetworkRule {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	rules := make([]NetworkRule, len(c.NetworkRules))
+	copy(rules, c.NetworkRules)
+	return rules
+}
+
+// GetOffline 返回离线页面配置（未配置时为 nil）
+func (c *Config) GetOffline() *OfflineConfig {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.Offline == nil {
+		return nil
+	}
+	o := *c.Offline
+	return &o
+}
+
+// GetProxy 返回认证代理配置（未配置时为 nil）
+func (c *Config) GetProxy() *ProxyConfig {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.Proxy == nil {
+		return nil
+	}
+	p := *c.Proxy
+	return &p
+}
+
+// GetTLS 返回本地 HTTPS 配置（未配置时为 nil）
+func (c *Config) GetTLS() *TLSConfig {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.TLS == nil {
+		return nil
+	}
+	t := *c.TLS
+	t.Names = append([]string(nil), t.Names...)
+	return &t
+}
+
+// GetTunnel 返回 SSH 隧道配置（未配置时为 nil）
+func (c *Config) GetTunnel() *TunnelConfig {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.Tunnel == nil {
+		return nil
+	}
+	t := *c.Tunnel
+	return &t
+}
+
+// GetWakeOnLan 返回网络唤醒配置（未配置时为 nil）
+func (c *Config) GetWakeOnLan() *WakeOnLanConfig {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.WakeOnLan == nil {
+		return nil
+	}
+	w := *c.WakeOnLan
+	return &w
+}
+
+// GetScheme 返回自定义协议配置（未配置时为 nil）
+func (c *Config) GetScheme() *SchemeConfig {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.Scheme == nil {
+		return nil
+	}
+	s := *c.Scheme
+	s.Routes = append([]SchemeRoute(nil), s.Routes...)
+	return &s
+}
+
+// GetRouter 返回路由模式配置（未配置时为 nil）
+func (c *Config) GetRouter() *RouterConfig {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.Router == nil {
+		return nil
+	}
+	r := *c.Router
+	r.Rules = append([]RouteRule(nil), r.Rules...)
+	return &r
+}
+
+// GetStartupURLs 返回托盘启动时打开的网页列表
+func (c *Config) GetStartupURLs() []StartupURL {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]StartupURL(nil), c.StartupURLs...)
+}
+
+// GetSchedules 返回定时任务列表
+func (c *Config) GetSchedules() []Schedule {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]Schedule(nil), c.Schedules...)
+}
+
+// GetKiosk 返回看板模式配置（未配置时为 nil）
+func (c *Config) GetKiosk() *KioskConfig {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.Kiosk == nil {
+		return nil
+	}
+	k := *c.Kiosk
+	return &k
+}
+
+// GetNotifications 返回是否显示桌面通知（未配置时为 true）
+func (c *Config) GetNotifications() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Notifications == nil || *c.Notifications
+}
+
+// GetMenu 返回自定义菜单项
+func (c *Config) GetMenu() []MenuEntry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	menu := make([]MenuEntry, len(c.Menu))
+	copy(menu, c.Menu)
+	return menu
+}
+
+// GetDefaultSite 返回标记为默认的站点（没有则返回 false）
+func (c *Config) GetDefaultSite() (Site, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, s := range c.Sites {
+		if s.Default && s.Target() != "" {
+			return s, true
+		}
+	}
+	return Site{}, false
 }
 
 func (c *Config) GetAutoStart() bool {
@@ -199,6 +560,11 @@ func (c *Config) SetOnChange(fn func(*Config)) {
 
 // Save 保存到外置文件（带防抖标记）
 func (c *Config) Save() {
+	// 静态配置模式没有外置文件
+	if c.path == "" {
+		return
+	}
+
 	c.mu.Lock()
 	c.saving = true
 	data, _ := json.MarshalIndent(c, "", "  ")
@@ -208,7 +574,7 @@ func (c *Config) Save() {
 	tmpPath := c.path + ".tmp"
 	err := os.WriteFile(tmpPath, data, 0644)
 	if err != nil {
-		log.Printf("Failed to write config to temp file: %v", err)
+		log.Printf(T("log.config_write_failed"), err)
 		c.mu.Lock()
 		c.saving = false
 		c.mu.Unlock()
@@ -218,7 +584,7 @@ func (c *Config) Save() {
 	// 原子重命名
 	err = os.Rename(tmpPath, c.path)
 	if err != nil {
-		log.Printf("Failed to rename config file: %v", err)
+		log.Printf(T("log.config_rename_failed"), err)
 		os.Remove(tmpPath)
 		c.mu.Lock()
 		c.saving = false
@@ -270,7 +636,7 @@ func (c *Config) StartWatching() error {
 						continue // 跳过自身触发的保存
 					}
 
-					log.Println("Config changed, reloading...")
+					log.Println(T("log.config_reloading"))
 					// 重新加载
 					data, err := os.ReadFile(c.path)
 					if err != nil {
@@ -279,21 +645,15 @@ func (c *Config) StartWatching() error {
 
 					var newCfg Config
 					if err := json.Unmarshal(data, &newCfg); err != nil {
+						// 编辑器保存过程中可能读到空文件，只报告有内容的解析错误
+						if len(bytes.TrimSpace(data)) > 0 {
+							reportConfigError(T("log.config_parse_failed"), err)
+						}
 						continue
 					}
 
 					c.mu.Lock()
-					if newCfg.Title != "" {
-						c.Title = newCfg.Title
-					}
-					if newCfg.URL != "" {
-						c.URL = newCfg.URL
-					}
-					if newCfg.Icon != "" {
-						c.Icon = newCfg.Icon
-					}
-					c.AutoStart = newCfg.AutoStart
-					c.TrayMode = newCfg.TrayMode
+					c.merge(&newCfg)
 					c.mu.Unlock()
 
 					if c.onChange != nil {
@@ -304,7 +664,7 @@ func (c *Config) StartWatching() error {
 				if !ok {
 					return
 				}
-				log.Println("Watcher error:", err)
+				log.Printf(T("log.watcher_error"), err)
 			}
 		}
 	}()
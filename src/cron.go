@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSpec 解析后的 cron 表达式（分 时 日 月 周），每个字段用位图表示允许的值
+type cronSpec struct {
+	minute, hour, dom, month, dow uint64
+	domRestricted, dowRestricted  bool // 日、周字段不是 *（包括 */2 这样的步长），两者都受限时按“或”匹配，与标准 cron 一致
+}
+
+// cronMacros 常用的简写
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+// parseCron 解析五段式 cron 表达式，支持 *、a-b、*/n、a-b/n、逗号列表，月份和星期可使用英文缩写
+func parseCron(expr string) (*cronSpec, error) {
+	text := strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(text)]; ok {
+		text = macro
+	}
+	fields := strings.Fields(text)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf(T("err.cron_fields"), expr)
+	}
+	// ? 是日、周字段中 * 的别名
+	for i, f := range fields {
+		if f == "?" {
+			fields[i] = "*"
+		}
+	}
+
+	spec := &cronSpec{}
+	var err error
+	if spec.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, err
+	}
+	if spec.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, err
+	}
+	if spec.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, err
+	}
+	if spec.month, err = parseCronField(fields[3], 1, 12, monthNames); err != nil {
+		return nil, err
+	}
+	days := make(map[string]int, len(weekdayNames))
+	for name, d := range weekdayNames {
+		days[name] = int(d)
+	}
+	if spec.dow, err = parseCronField(fields[4], 0, 7, days); err != nil {
+		return nil, err
+	}
+	// 周日可写作 0 或 7
+	if spec.dow&(1<<7) != 0 {
+		spec.dow |= 1
+	}
+	spec.domRestricted = fields[2] != "*"
+	spec.dowRestricted = fields[4] != "*"
+	return spec, nil
+}
+
+// parseCronField 解析一个字段，返回允许值的位图
+func parseCronField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf(T("err.cron_field"), field)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		if rangePart != "*" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = cronValue(from, names); err != nil {
+				return 0, fmt.Errorf(T("err.cron_field"), field)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = cronValue(to, names); err != nil {
+					return 0, fmt.Errorf(T("err.cron_field"), field)
+				}
+			} else if hasStep {
+				hi = max // a/n 表示从 a 开始每 n 个
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf(T("err.cron_field"), field)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func cronValue(s string, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	return strconv.Atoi(s)
+}
+
+// dayMatches 判断日期是否匹配：日、周只有一个受限时按该字段匹配，都受限时满足任意一个即可
+func (c *cronSpec) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case c.domRestricted && c.dowRestricted:
+		return dom || dow
+	case c.domRestricted:
+		return dom
+	case c.dowRestricted:
+		return dow
+	default:
+		return true
+	}
+}
+
+// Next 返回 t 之后（不含 t 所在的分钟）第一个匹配的时间，5 年内没有匹配时返回零值
+func (c *cronSpec) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
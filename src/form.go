@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/user"
+	"sort"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+	"time"
+)
+
+// FormConfig 表单启动：通过本地一次性页面自动提交表单（如 SSO 跳转、租户选择）
+type FormConfig struct {
+	Method  string            `json:"method,omitempty"`  // POST（默认）或 GET
+	Action  string            `json:"action"`            // 表单提交地址
+	Enctype string            `json:"enctype,omitempty"` // 编码方式，默认 application/x-www-form-urlencoded，可选 multipart/form-data
+	Fields  map[string]string `json:"fields,omitempty"`  // 表单字段，值支持模板（如 {{.User}}、{{env "TENANT"}}、{{secret "sso"}}）
+}
+
+// formTTL 启动页面的有效期，超时未访问则失效
+const formTTL = 2 * time.Minute
+
+// 内置自动提交页面模板
+//
+//go:embed assets/form.html
+var formTemplateText string
+
+var formTemplate = template.Must(template.New("form").Parse(formTemplateText))
+
+// formField 表单字段（按名称排序，保证页面输出稳定）
+type formField struct {
+	Name  string
+	Value string
+}
+
+// formPage 自动提交页面模板数据
+type formPage struct {
+	Lang       string
+	Title      string
+	Method     string
+	Action     string
+	Enctype    string
+	Fields     []formField
+	Submitting string
+	Continue   string
+}
+
+// formValues 字段模板可使用的变量
+type formValues struct {
+	URL      string // 当前目标地址
+	Profile  string // 当前环境名称
+	Title    string // 程序标题
+	User     string // 当前系统用户名
+	Hostname string // 计算机名
+}
+
+var (
+	formMu      sync.Mutex
+	formPending = make(map[string]*formLaunch) // token -> 待提交的表单
+)
+
+// formLaunch 已生成、尚未使用的启动页面
+type formLaunch struct {
+	page    formPage
+	expires time.Time
+}
+
+func init() {
+	localServer.Handle("form/", http.HandlerFunc(serveForm))
+}
+
+// openForm 生成一次性启动页面并在浏览器中打开
+func openForm(title string, f *FormConfig, b *BrowserConfig) error {
+	page, err := buildFormPage(title, f)
+	if err != nil {
+		return err
+	}
+	token, err := newFormToken()
+	if err != nil {
+		return err
+	}
+	if !localServer.Running() {
+		if err := localServer.Start(0); err != nil {
+			return err
+		}
+	}
+
+	formMu.Lock()
+	now := time.Now()
+	for k, p := range formPending {
+		if now.After(p.expires) {
+			delete(formPending, k)
+		}
+	}
+	formPending[token] = &formLaunch{page: page, expires: now.Add(formTTL)}
+	formMu.Unlock()
+
+	log.Printf(T("log.form_open"), strings.ToUpper(page.Method), page.Action)
+	return openURLWith(localServer.URL(internalPrefix+"form/"+token), b)
+}
+
+// buildFormPage 校验表单配置并展开字段模板
+func buildFormPage(title string, f *FormConfig) (formPage, error) {
+	method := strings.ToUpper(f.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+	if method != http.MethodPost && method != http.MethodGet {
+		return formPage{}, fmt.Errorf(T("err.form_method"), f.Method)
+	}
+	u, err := url.Parse(f.Action)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return formPage{}, fmt.Errorf(T("err.form_action"), f.Action)
+	}
+	if title == "" {
+		title = config.GetTitle()
+	}
+
+	page := formPage{
+		Lang:       currentLanguage(),
+		Title:      title,
+		Method:     strings.ToLower(method),
+		Action:     u.String(),
+		Enctype:    f.Enctype,
+		Submitting: T("form.submitting"),
+		Continue:   T("form.continue"),
+	}
+	values := currentFormValues()
+	names := make([]string, 0, len(f.Fields))
+	for name := range f.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		v, err := expandFormValue(f.Fields[name], values)
+		if err != nil {
+			return formPage{}, fmt.Errorf(T("err.form_field"), name, err)
+		}
+		page.Fields = append(page.Fields, formField{Name: name, Value: v})
+	}
+	return page, nil
+}
+
+func currentFormValues() formValues {
+	v := formValues{
+		URL:     targetURL(),
+		Profile: config.GetActiveProfile(),
+		Title:   config.GetTitle(),
+	}
+	if u, err := user.Current(); err == nil {
+		v.User = u.Username
+		// Windows 上为 DOMAIN\user，只保留用户名
+		if i := strings.LastIndex(v.User, `\`); i >= 0 {
+			v.User = v.User[i+1:]
+		}
+	}
+	v.Hostname, _ = os.Hostname()
+	return v
+}
+
+// formFuncs 字段模板函数：env 读取环境变量，secret 读取系统凭据存储
+var formFuncs = texttemplate.FuncMap{
+	"env": os.Getenv,
+	"secret": func(service string, account ...string) (string, error) {
+		a := ""
+		if len(account) > 0 {
+			a = account[0]
+		}
+		return readSecret(service, a)
+	},
+}
+
+// expandFormValue 展开字段值中的模板，不含 {{ 时原样返回
+func expandFormValue(text string, values formValues) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tmpl, err := texttemplate.New("field").Funcs(formFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, values); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func newFormToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// serveForm 输出自动提交页面，每个 token 只能使用一次
+func serveForm(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.URL.Path, internalPrefix+"form/")
+
+	formMu.Lock()
+	launch := formPending[token]
+	delete(formPending, token)
+	formMu.Unlock()
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	if launch == nil || time.Now().After(launch.expires) {
+		http.Error(w, T("form.expired"), http.StatusGone)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := formTemplate.Execute(w, launch.page); err != nil {
+		log.Printf(T("log.form_template_failed"), err)
+	}
+}
@@ -0,0 +1,41 @@
+//go:build darwin
+
+package main
+
+import (
+	"errors"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+var arpMAC = regexp.MustCompile(`at ([0-9a-fA-F:]+) `)
+
+// gatewayMAC 返回默认网关的 MAC 地址（route / arp 命令）
+func gatewayMAC() (string, error) {
+	out, err := exec.Command("route", "-n", "get", "default").Output()
+	if err != nil {
+		return "", err
+	}
+	var gw string
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "gateway:") {
+			gw = strings.TrimSpace(strings.TrimPrefix(line, "gateway:"))
+			break
+		}
+	}
+	if gw == "" {
+		return "", errors.New(T("err.no_gateway"))
+	}
+
+	out, err = exec.Command("arp", "-n", gw).Output()
+	if err != nil {
+		return "", err
+	}
+	m := arpMAC.FindStringSubmatch(string(out))
+	if m == nil {
+		return "", errors.New(T("err.gateway_mac", gw))
+	}
+	return normalizeMAC(m[1]), nil
+}
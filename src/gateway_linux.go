@@ -0,0 +1,63 @@
+//go:build linux
+
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"net"
+	"os"
+	"strings"
+)
+
+// defaultGateway 从 /proc/net/route 读取默认网关地址
+func defaultGateway() (net.IP, error) {
+	f, err := os.Open("/proc/net/route")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // 表头
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || fields[1] != "00000000" {
+			continue
+		}
+		raw, err := hex.DecodeString(fields[2])
+		if err != nil || len(raw) != 4 {
+			continue
+		}
+		// 字段为小端序
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, binary.LittleEndian.Uint32(raw))
+		return ip, nil
+	}
+	return nil, errors.New(T("err.no_gateway"))
+}
+
+// gatewayMAC 返回默认网关的 MAC 地址（从 ARP 缓存读取）
+func gatewayMAC() (string, error) {
+	gw, err := defaultGateway()
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open("/proc/net/arp")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // 表头
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 4 && fields[0] == gw.String() {
+			return normalizeMAC(fields[3]), nil
+		}
+	}
+	return "", errors.New(T("err.gateway_mac", gw))
+}
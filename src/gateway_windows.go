@@ -0,0 +1,46 @@
+//go:build windows
+
+package main
+
+import (
+	"errors"
+	"net"
+	"os/exec"
+	"strings"
+)
+
+// gatewayMAC 返回默认网关的 MAC 地址（route print / arp -a）
+func gatewayMAC() (string, error) {
+	cmd := exec.Command("route", "-4", "print", "0.0.0.0")
+	hideWindow(cmd)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	// 默认路由行：0.0.0.0  0.0.0.0  <网关>  <接口>  <跃点数>
+	var gw string
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 5 && fields[0] == "0.0.0.0" && fields[1] == "0.0.0.0" && net.ParseIP(fields[2]) != nil {
+			gw = fields[2]
+			break
+		}
+	}
+	if gw == "" {
+		return "", errors.New(T("err.no_gateway"))
+	}
+
+	cmd = exec.Command("arp", "-a", gw)
+	hideWindow(cmd)
+	out, err = cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == gw {
+			return normalizeMAC(fields[1]), nil
+		}
+	}
+	return "", errors.New(T("err.gateway_mac", gw))
+}
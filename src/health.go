@@ -0,0 +1,406 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"log"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HealthConfig 后台健康检查：定期请求目标地址，在托盘图标和提示中显示状态
+type HealthConfig struct {
+	URL           string   `json:"url,omitempty"`           // 检查地址，默认为当前目标地址
+	Interval      Duration `json:"interval,omitempty"`      // 检查间隔，默认 30s
+	Timeout       Duration `json:"timeout,omitempty"`       // 单次请求超时，默认 5s
+	ExpectStatus  []int    `json:"expectStatus,omitempty"`  // 期望的状态码，默认任意 2xx
+	Contains      string   `json:"contains,omitempty"`      // 响应内容需包含的文本
+	JSONPath      string   `json:"jsonPath,omitempty"`      // 响应 JSON 中的字段路径，如 status、checks.0.state
+	JSONValue     string   `json:"jsonValue,omitempty"`     // 字段期望值（按文本比较），空则要求字段存在且不为 false / null
+	SlowThreshold Duration `json:"slowThreshold,omitempty"` // 响应时间超过该值视为降级，默认不检查
+	Threshold     int      `json:"threshold,omitempty"`     // 连续多少次结果相同才切换状态，默认 2
+	History       int      `json:"history,omitempty"`       // 保留的检查记录条数，默认 50
+}
+
+// 健康状态
+const (
+	healthUnknown  = "unknown"  // 尚未检查
+	healthHealthy  = "healthy"  // 符合全部期望
+	healthDegraded = "degraded" // 有响应，但状态码、内容不符合期望或响应过慢
+	healthDown     = "down"     // 无法连接或请求超时
+)
+
+// HealthCheck 一次检查的结果
+type HealthCheck struct {
+	Time    time.Time `json:"time"`
+	State   string    `json:"state"`
+	Status  int       `json:"status,omitempty"`
+	Latency int64     `json:"latencyMs"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// HealthTransition 一次状态切换
+type HealthTransition struct {
+	Time time.Time `json:"time"`
+	From string    `json:"from"`
+	To   string    `json:"to"`
+}
+
+// HealthStatus IPC status 中的健康状态
+type HealthStatus struct {
+	State       string             `json:"state"`
+	Since       time.Time          `json:"since"`
+	URL         string             `json:"url"`
+	Last        *HealthCheck       `json:"last,omitempty"`
+	History     []HealthCheck      `json:"history"`
+	Transitions []HealthTransition `json:"transitions"`
+}
+
+// healthClient 健康检查使用的 HTTP 客户端（跟随重定向，以最终页面为准）
+var healthClient = &http.Client{Transport: localTrust}
+
+type healthMonitor struct {
+	cfg    HealthConfig
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu          sync.Mutex
+	state       string
+	since       time.Time
+	pending     string // 待切换的状态
+	pendingN    int    // 待切换状态已连续出现的次数
+	url         string
+	checks      []HealthCheck
+	transitions []HealthTransition
+}
+
+var (
+	healthMu sync.Mutex
+	health   *healthMonitor
+)
+
+// applyHealth 按配置启动、重启或停止健康检查（启动和热重载时调用）
+func applyHealth(cfg *HealthConfig) {
+	healthMu.Lock()
+	old := health
+	if old != nil && cfg != nil && reflect.DeepEqual(old.cfg, *cfg) {
+		healthMu.Unlock()
+		return
+	}
+	health = nil
+	healthMu.Unlock()
+
+	// 在锁外等待旧的检查结束（检查结果会回调托盘更新）
+	if old != nil {
+		old.stop()
+		setTrayStatus("health", "")
+		updateTrayIcon()
+	}
+	if cfg != nil {
+		m := newHealthMonitor(*cfg)
+		healthMu.Lock()
+		health = m
+		healthMu.Unlock()
+		m.start()
+	}
+}
+
+// stopHealth 停止健康检查
+func stopHealth() {
+	healthMu.Lock()
+	m := health
+	health = nil
+	healthMu.Unlock()
+	if m != nil {
+		m.stop()
+	}
+}
+
+func currentHealth() *healthMonitor {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	return health
+}
+
+func newHealthMonitor(cfg HealthConfig) *healthMonitor {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &healthMonitor{
+		cfg:    cfg,
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		state:  healthUnknown,
+		since:  time.Now(),
+	}
+}
+
+// start 开始定期检查
+func (m *healthMonitor) start() {
+	go m.loop(m.ctx)
+}
+
+func (m *healthMonitor) stop() {
+	m.cancel()
+	<-m.done
+}
+
+func (m *healthMonitor) loop(ctx context.Context) {
+	defer close(m.done)
+	ticker := time.NewTicker(m.cfg.Interval.Or(30 * time.Second))
+	defer ticker.Stop()
+	for {
+		result := m.check(ctx)
+		if ctx.Err() != nil {
+			// 停止、重载或退出中断了检查，结果不代表服务状态
+			return
+		}
+		m.record(result)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check 执行一次检查
+func (m *healthMonitor) check(ctx context.Context) HealthCheck {
+	target := m.cfg.URL
+	if target == "" {
+		target = targetURL()
+	}
+	m.mu.Lock()
+	m.url = target
+	m.mu.Unlock()
+
+	result := HealthCheck{Time: time.Now(), State: healthDown}
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.Timeout.Or(5*time.Second))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	start := time.Now()
+	resp, err := healthClient.Do(req)
+	if err != nil {
+		result.Latency = time.Since(start).Milliseconds()
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+	// 只读取前 1MB 用于匹配内容
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	elapsed := time.Since(start)
+	result.Latency = elapsed.Milliseconds()
+	result.Status = resp.StatusCode
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.State = healthDegraded
+	if err := m.expect(resp.StatusCode, body); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if slow := time.Duration(m.cfg.SlowThreshold); slow > 0 && elapsed > slow {
+		result.Error = T("err.health_slow", elapsed.Round(time.Millisecond))
+		return result
+	}
+	result.State = healthHealthy
+	return result
+}
+
+// expect 检查状态码和响应内容是否符合期望
+func (m *healthMonitor) expect(status int, body []byte) error {
+	if len(m.cfg.ExpectStatus) > 0 {
+		ok := false
+		for _, s := range m.cfg.ExpectStatus {
+			ok = ok || s == status
+		}
+		if !ok {
+			return fmt.Errorf(T("err.health_status"), status)
+		}
+	} else if status < 200 || status > 299 {
+		return fmt.Errorf(T("err.health_status"), status)
+	}
+
+	if m.cfg.Contains != "" && !strings.Contains(string(body), m.cfg.Contains) {
+		return fmt.Errorf(T("err.health_contains"), m.cfg.Contains)
+	}
+
+	if m.cfg.JSONPath != "" {
+		var doc interface{}
+		if err := json.Unmarshal(body, &doc); err != nil {
+			return fmt.Errorf(T("err.health_json"), err)
+		}
+		value, ok := jsonLookup(doc, m.cfg.JSONPath)
+		if !ok {
+			return fmt.Errorf(T("err.health_json_missing"), m.cfg.JSONPath)
+		}
+		if m.cfg.JSONValue != "" {
+			if got := jsonText(value); got != m.cfg.JSONValue {
+				return fmt.Errorf(T("err.health_json_value"), m.cfg.JSONPath, got, m.cfg.JSONValue)
+			}
+		} else if value == nil || value == false {
+			return fmt.Errorf(T("err.health_json_value"), m.cfg.JSONPath, jsonText(value), "true")
+		}
+	}
+	return nil
+}
+
+// jsonLookup 按点分隔的路径查找字段，数组使用下标（如 checks.0.state）
+func jsonLookup(doc interface{}, path string) (interface{}, bool) {
+	cur := doc
+	for _, key := range strings.Split(path, ".") {
+		switch v := cur.(type) {
+		case map[string]interface{}:
+			next, ok := v[key]
+			if !ok {
+				return nil, false
+			}
+			cur = next
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			cur = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// jsonText 将 JSON 值转为文本用于比较
+func jsonText(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case nil:
+		return "null"
+	default:
+		data, _ := json.Marshal(value)
+		return string(data)
+	}
+}
+
+// record 记录检查结果，连续 threshold 次结果相同时切换状态
+func (m *healthMonitor) record(result HealthCheck) {
+	m.mu.Lock()
+	limit := m.cfg.History
+	if limit <= 0 {
+		limit = 50
+	}
+	m.checks = append(m.checks, result)
+	if len(m.checks) > limit {
+		m.checks = m.checks[len(m.checks)-limit:]
+	}
+
+	from := m.state
+	switch {
+	case result.State == m.state:
+		m.pending, m.pendingN = "", 0
+	case m.state == healthUnknown:
+		// 首次检查直接生效
+		m.setStateLocked(result.State, result.Time, limit)
+	default:
+		if result.State != m.pending {
+			m.pending, m.pendingN = result.State, 0
+		}
+		m.pendingN++
+		threshold := m.cfg.Threshold
+		if threshold <= 0 {
+			threshold = 2
+		}
+		if m.pendingN >= threshold {
+			m.setStateLocked(result.State, result.Time, limit)
+		}
+	}
+	to := m.state
+	m.mu.Unlock()
+
+	if from != to {
+		if result.Error != "" {
+			log.Printf(T("log.health_changed_error"), T("health."+from), T("health."+to), result.Error)
+		} else {
+			log.Printf(T("log.health_changed"), T("health."+from), T("health."+to))
+		}
+		// 首次检查结果正常时不打扰用户
+		if from != healthUnknown || to != healthHealthy {
+			notifyHealth(from, to, result.Error)
+		}
+		updateTrayIcon()
+	}
+	setTrayStatus("health", T("tray.health", T("health."+to), result.Latency))
+}
+
+func (m *healthMonitor) setStateLocked(state string, at time.Time, limit int) {
+	m.transitions = append(m.transitions, HealthTransition{Time: at, From: m.state, To: state})
+	if len(m.transitions) > limit {
+		m.transitions = m.transitions[len(m.transitions)-limit:]
+	}
+	m.state = state
+	m.since = at
+	m.pending, m.pendingN = "", 0
+}
+
+// State 返回当前健康状态
+func (m *healthMonitor) State() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state
+}
+
+// Status 返回健康状态和检查记录
+func (m *healthMonitor) Status() HealthStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st := HealthStatus{
+		State:       m.state,
+		Since:       m.since,
+		URL:         m.url,
+		History:     append([]HealthCheck{}, m.checks...),
+		Transitions: append([]HealthTransition{}, m.transitions...),
+	}
+	if n := len(m.checks); n > 0 {
+		last := m.checks[n-1]
+		st.Last = &last
+	}
+	return st
+}
+
+// healthBadge 返回托盘图标上的状态标记颜色，正常或未启用时返回 false
+func healthBadge() (color.Color, bool) {
+	m := currentHealth()
+	if m == nil {
+		return nil, false
+	}
+	switch m.State() {
+	case healthDegraded:
+		return color.NRGBA{0xf5, 0x9e, 0x0b, 0xff}, true
+	case healthDown:
+		return color.NRGBA{0xef, 0x44, 0x44, 0xff}, true
+	}
+	return nil, false
+}
+
+func init() {
+	registerStatus("health", func() interface{} {
+		if m := currentHealth(); m != nil {
+			return m.Status()
+		}
+		return nil
+	})
+}
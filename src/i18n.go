@@ -0,0 +1,174 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// 内嵌语言包，品牌定制版可在 assets/locales 下追加 <语言>.json
+//
+//go:embed assets/locales/*.json
+var embeddedLocales embed.FS
+
+const (
+	defaultLanguage  = "zh-CN" // 无法识别系统语言时使用
+	fallbackLanguage = "en"    // 当前语言缺少词条时回退
+)
+
+// i18n 在包变量初始化阶段加载（flag 说明等包级变量依赖 T）
+var i18n = newLocaleState()
+
+type localeState struct {
+	mu       sync.RWMutex
+	catalogs map[string]map[string]string // 语言 -> 词条
+	lang     string
+}
+
+// newLocaleState 加载内嵌语言包并按系统语言初始化
+func newLocaleState() *localeState {
+	st := &localeState{catalogs: make(map[string]map[string]string)}
+	entries, _ := embeddedLocales.ReadDir("assets/locales")
+	for _, e := range entries {
+		data, err := embeddedLocales.ReadFile(path.Join("assets/locales", e.Name()))
+		if err == nil {
+			st.add(e.Name(), data)
+		}
+	}
+	st.lang = st.match(systemLanguage())
+	return st
+}
+
+// add 合并语言包，同名词条覆盖已有内容
+func (st *localeState) add(name string, data []byte) error {
+	var messages map[string]string
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return err
+	}
+	lang := strings.TrimSuffix(name, filepath.Ext(name))
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	catalog := st.catalogs[lang]
+	if catalog == nil {
+		catalog = make(map[string]string)
+		st.catalogs[lang] = catalog
+	}
+	for k, v := range messages {
+		catalog[k] = v
+	}
+	return nil
+}
+
+// loadLocaleDir 加载目录中的语言包（数据目录下的 locales），用于覆盖或扩展内嵌语言包
+func loadLocaleDir(dir string) {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err == nil {
+			err = i18n.add(filepath.Base(f), data)
+		}
+		if err != nil {
+			log.Printf(T("log.locale_load_failed"), f, err)
+		}
+	}
+}
+
+// setLanguage 切换界面语言，lang 为空时使用系统语言，返回实际使用的语言
+func setLanguage(lang string) string {
+	if lang == "" {
+		lang = systemLanguage()
+	}
+	i18n.mu.Lock()
+	defer i18n.mu.Unlock()
+	i18n.lang = i18n.match(lang)
+	return i18n.lang
+}
+
+// currentLanguage 返回当前界面语言
+func currentLanguage() string {
+	i18n.mu.RLock()
+	defer i18n.mu.RUnlock()
+	return i18n.lang
+}
+
+// match 在已加载的语言包中查找最匹配的语言（调用方负责加锁）
+// 依次尝试完全匹配（zh-CN）、主语言匹配（zh-TW -> zh-CN），最后使用默认语言
+func (st *localeState) match(tag string) string {
+	tag = normalizeLanguage(tag)
+	if tag == "" {
+		return defaultLanguage
+	}
+	for lang := range st.catalogs {
+		if strings.EqualFold(lang, tag) {
+			return lang
+		}
+	}
+	base := strings.SplitN(tag, "-", 2)[0]
+	var candidates []string
+	for lang := range st.catalogs {
+		if strings.EqualFold(strings.SplitN(lang, "-", 2)[0], base) {
+			candidates = append(candidates, lang)
+		}
+	}
+	if len(candidates) > 0 {
+		// 多个候选时取名称最短的（如 en 优先于 en-GB），保证结果稳定
+		best := candidates[0]
+		for _, c := range candidates[1:] {
+			if len(c) < len(best) || (len(c) == len(best) && c < best) {
+				best = c
+			}
+		}
+		return best
+	}
+	return defaultLanguage
+}
+
+// normalizeLanguage 将 zh_CN.UTF-8、en_US@euro 等形式规范为 zh-CN、en-US
+func normalizeLanguage(tag string) string {
+	if i := strings.IndexAny(tag, ".@"); i >= 0 {
+		tag = tag[:i]
+	}
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+	if tag == "C" || tag == "POSIX" {
+		return ""
+	}
+	return tag
+}
+
+// systemLanguage 从环境变量或系统设置获取语言
+func systemLanguage() string {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := normalizeLanguage(os.Getenv(key)); v != "" {
+			return v
+		}
+	}
+	return platformLanguage()
+}
+
+// T 返回当前语言的词条，args 非空时按 fmt 格式化
+// 当前语言缺少词条时依次回退到英文、默认语言，都没有则返回 key
+func T(key string, args ...interface{}) string {
+	i18n.mu.RLock()
+	msg, ok := i18n.catalogs[i18n.lang][key]
+	if !ok {
+		msg, ok = i18n.catalogs[fallbackLanguage][key]
+	}
+	if !ok {
+		msg, ok = i18n.catalogs[defaultLanguage][key]
+	}
+	i18n.mu.RUnlock()
+	if !ok {
+		msg = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
@@ -0,0 +1,413 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/srwiley/oksvg"
+	"github.com/srwiley/rasterx"
+	"golang.org/x/image/draw"
+)
+
+// 托盘图标规范化：将 PNG / ICO / SVG 转换为各平台 systray 需要的格式
+// Windows 需要 ICO，Linux（StatusNotifierItem）和 macOS 使用 PNG
+
+var (
+	icoSizes    = []int{16, 24, 32, 48, 64, 256} // 生成 ICO 包含的尺寸
+	pngIconSize = 64                             // 生成 PNG 的尺寸
+	svgSize     = 256                            // SVG 渲染尺寸
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// trayIconExt 当前平台托盘图标格式
+func trayIconExt() string {
+	if runtime.GOOS == "windows" {
+		return ".ico"
+	}
+	return ".png"
+}
+
+// loadTrayIcon 读取图标文件并转换为当前平台需要的格式
+func loadTrayIcon(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return normalizeIcon(data)
+}
+
+// normalizeIcon 转换图标格式，结果按内容哈希缓存到数据目录
+func normalizeIcon(data []byte) ([]byte, error) {
+	sum := sha256.Sum256(data)
+	ext := trayIconExt()
+	var cachePath string
+	if DataDir != "" {
+		cachePath = filepath.Join(DataDir, "icons", hex.EncodeToString(sum[:8])+ext)
+		if cached, err := os.ReadFile(cachePath); err == nil {
+			return cached, nil
+		}
+	}
+
+	img, err := decodeIcon(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []byte
+	if ext == ".ico" {
+		out, err = encodeICO(img, icoSizes)
+	} else {
+		out, err = encodePNG(resizeSquare(img, pngIconSize))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cachePath != "" {
+		if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err == nil {
+			if err := os.WriteFile(cachePath, out, 0644); err != nil {
+				log.Printf(T("log.icon_cache_failed"), err)
+			}
+		}
+	}
+	return out, nil
+}
+
+// decodeIcon 根据内容识别并解码 PNG / ICO / SVG
+func decodeIcon(data []byte) (image.Image, error) {
+	switch {
+	case bytes.HasPrefix(data, pngSignature):
+		return png.Decode(bytes.NewReader(data))
+	case len(data) >= 6 && binary.LittleEndian.Uint16(data[0:]) == 0 && binary.LittleEndian.Uint16(data[2:]) == 1:
+		return decodeICO(data)
+	case isSVG(data):
+		return renderSVG(data, svgSize)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf(T("err.icon_format"), err)
+	}
+	return img, nil
+}
+
+func isSVG(data []byte) bool {
+	head := data
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	return bytes.Contains(bytes.ToLower(head), []byte("<svg"))
+}
+
+// renderSVG 将 SVG 渲染为 size×size 位图
+func renderSVG(data []byte, size int) (image.Image, error) {
+	icon, err := oksvg.ReadIconStream(bytes.NewReader(data), oksvg.IgnoreErrorMode)
+	if err != nil {
+		return nil, fmt.Errorf(T("err.svg_parse"), err)
+	}
+	icon.SetTarget(0, 0, float64(size), float64(size))
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	scanner := rasterx.NewScannerGV(size, size, img, img.Bounds())
+	icon.Draw(rasterx.NewDasher(size, size, scanner), 1)
+	return img, nil
+}
+
+// decodeICO 解码 ICO 中尺寸最大的一张图
+func decodeICO(data []byte) (image.Image, error) {
+	count := int(binary.LittleEndian.Uint16(data[4:]))
+	if count == 0 || len(data) < 6+count*16 {
+		return nil, errors.New(T("err.ico_corrupt"))
+	}
+
+	best, bestArea, bestBpp := -1, 0, 0
+	for i := 0; i < count; i++ {
+		entry := data[6+i*16:]
+		w, h := int(entry[0]), int(entry[1])
+		if w == 0 {
+			w = 256
+		}
+		if h == 0 {
+			h = 256
+		}
+		bpp := int(binary.LittleEndian.Uint16(entry[6:]))
+		if area := w * h; area > bestArea || (area == bestArea && bpp > bestBpp) {
+			best, bestArea, bestBpp = i, area, bpp
+		}
+	}
+
+	entry := data[6+best*16:]
+	size := int(binary.LittleEndian.Uint32(entry[8:]))
+	offset := int(binary.LittleEndian.Uint32(entry[12:]))
+	if offset < 0 || size <= 0 || offset+size > len(data) {
+		return nil, errors.New(T("err.ico_corrupt"))
+	}
+	img := data[offset : offset+size]
+	if bytes.HasPrefix(img, pngSignature) {
+		return png.Decode(bytes.NewReader(img))
+	}
+	return decodeDIB(img)
+}
+
+// decodeDIB 解码 ICO 内嵌的 BMP（不含文件头，高度为 XOR + AND 两部分之和）
+func decodeDIB(data []byte) (image.Image, error) {
+	if len(data) < 40 {
+		return nil, errors.New(T("err.ico_corrupt"))
+	}
+	headerSize := int(binary.LittleEndian.Uint32(data[0:]))
+	w := int(int32(binary.LittleEndian.Uint32(data[4:])))
+	h := int(int32(binary.LittleEndian.Uint32(data[8:]))) / 2
+	bpp := int(binary.LittleEndian.Uint16(data[14:]))
+	colorsUsed := int(binary.LittleEndian.Uint32(data[32:]))
+	if w <= 0 || h <= 0 || w > 1024 || h > 1024 {
+		return nil, errors.New(T("err.ico_corrupt"))
+	}
+
+	pos := headerSize
+	var palette []color.NRGBA
+	if bpp <= 8 {
+		n := colorsUsed
+		if n == 0 {
+			n = 1 << bpp
+		}
+		if pos+n*4 > len(data) {
+			return nil, errors.New(T("err.ico_corrupt"))
+		}
+		for i := 0; i < n; i++ {
+			p := data[pos+i*4:]
+			palette = append(palette, color.NRGBA{R: p[2], G: p[1], B: p[0], A: 0xff})
+		}
+		pos += n * 4
+	}
+
+	xorStride := ((w*bpp + 31) / 32) * 4
+	andStride := ((w + 31) / 32) * 4
+	if pos+xorStride*h > len(data) {
+		return nil, errors.New(T("err.ico_corrupt"))
+	}
+	xor := data[pos : pos+xorStride*h]
+	var and []byte
+	if end := pos + xorStride*h + andStride*h; end <= len(data) {
+		and = data[pos+xorStride*h : end]
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	hasAlpha := false
+	for y := 0; y < h; y++ {
+		row := xor[(h-1-y)*xorStride:] // 位图按自下而上存储
+		for x := 0; x < w; x++ {
+			var c color.NRGBA
+			switch bpp {
+			case 32:
+				c = color.NRGBA{R: row[x*4+2], G: row[x*4+1], B: row[x*4], A: row[x*4+3]}
+				if c.A != 0 {
+					hasAlpha = true
+				}
+			case 24:
+				c = color.NRGBA{R: row[x*3+2], G: row[x*3+1], B: row[x*3], A: 0xff}
+			case 8, 4, 1:
+				bit := x * bpp
+				idx := int(row[bit/8]>>(8-bpp-bit%8)) & (1<<bpp - 1)
+				if idx < len(palette) {
+					c = palette[idx]
+				}
+			default:
+				return nil, fmt.Errorf(T("err.ico_bpp"), bpp)
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+
+	// 无 Alpha 通道时使用 AND 掩码确定透明区域
+	if !hasAlpha && and != nil {
+		for y := 0; y < h; y++ {
+			row := and[(h-1-y)*andStride:]
+			for x := 0; x < w; x++ {
+				if row[x/8]&(0x80>>(x%8)) != 0 {
+					img.SetNRGBA(x, y, color.NRGBA{})
+				}
+			}
+		}
+	} else if !hasAlpha && bpp == 32 {
+		// 32 位但 Alpha 全为 0，视为不透明
+		for i := 3; i < len(img.Pix); i += 4 {
+			img.Pix[i] = 0xff
+		}
+	}
+	return img, nil
+}
+
+// resizeSquare 等比缩放并居中到 size×size 的透明画布
+func resizeSquare(src image.Image, size int) image.Image {
+	b := src.Bounds()
+	w, h := size, size
+	if b.Dx() > b.Dy() {
+		h = size * b.Dy() / b.Dx()
+	} else if b.Dy() > b.Dx() {
+		w = size * b.Dx() / b.Dy()
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+	rect := image.Rect((size-w)/2, (size-h)/2, (size-w)/2+w, (size-h)/2+h)
+	draw.CatmullRom.Scale(dst, rect, src, b, draw.Over, nil)
+	return dst
+}
+
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeICO 生成包含多个尺寸的 ICO（各尺寸以 PNG 存储，Vista 及以上支持）
+func encodeICO(img image.Image, sizes []int) ([]byte, error) {
+	images := make([][]byte, len(sizes))
+	for i, size := range sizes {
+		data, err := encodePNG(resizeSquare(img, size))
+		if err != nil {
+			return nil, err
+		}
+		images[i] = data
+	}
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, [3]uint16{0, 1, uint16(len(sizes))})
+	offset := 6 + 16*len(sizes)
+	for i, size := range sizes {
+		dim := byte(size)
+		if size >= 256 {
+			dim = 0 // 0 表示 256
+		}
+		buf.Write([]byte{dim, dim, 0, 0})
+		binary.Write(&buf, binary.LittleEndian, [2]uint16{1, 32})
+		binary.Write(&buf, binary.LittleEndian, [2]uint32{uint32(len(images[i])), uint32(offset)})
+		offset += len(images[i])
+	}
+	for _, data := range images {
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// iconWatcher 监控图标文件变化
+type iconWatcher struct {
+	mu       sync.Mutex
+	watcher  *fsnotify.Watcher
+	path     string // 当前监控的图标文件绝对路径
+	timer    *time.Timer
+	onChange func()
+}
+
+func newIconWatcher(onChange func()) (*iconWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &iconWatcher{watcher: watcher, onChange: onChange}
+	go w.loop()
+	return w, nil
+}
+
+// SetPath 切换监控的图标文件，path 为空表示停止监控
+func (w *iconWatcher) SetPath(path string) {
+	if path != "" {
+		path, _ = filepath.Abs(path)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if path == w.path {
+		return
+	}
+	// 监控目录（监控文件本身在 Windows 下不可靠，且编辑器保存时可能替换文件）
+	if w.path != "" {
+		w.watcher.Remove(filepath.Dir(w.path))
+	}
+	w.path = path
+	if path != "" {
+		if err := w.watcher.Add(filepath.Dir(path)); err != nil {
+			log.Printf(T("log.icon_watch_path_failed"), path, err)
+		}
+	}
+}
+
+func (w *iconWatcher) loop() {
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			w.mu.Lock()
+			match := w.path != "" && filepath.Clean(event.Name) == w.path
+			if match {
+				// 防抖：编辑器保存时可能连续触发多次事件
+				if w.timer != nil {
+					w.timer.Stop()
+				}
+				w.timer = time.AfterFunc(300*time.Millisecond, w.onChange)
+			}
+			w.mu.Unlock()
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf(T("log.icon_watcher_error"), err)
+		}
+	}
+}
+
+func (w *iconWatcher) Close() {
+	w.watcher.Close()
+}
+
+// badgeIcon 在托盘图标右下角绘制圆形状态标记，返回当前平台格式的图标
+func badgeIcon(data []byte, c color.Color) ([]byte, error) {
+	img, err := decodeIcon(data)
+	if err != nil {
+		return nil, err
+	}
+	size := svgSize
+	dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+	draw.Copy(dst, image.Point{}, resizeSquare(img, size), image.Rect(0, 0, size, size), draw.Src, nil)
+
+	// 白色描边使标记在深色和浅色背景上都清晰
+	r := float64(size) * 0.22
+	cx, cy := float64(size)-r-1, float64(size)-r-1
+	fillCircle(dst, cx, cy, r, color.White)
+	fillCircle(dst, cx, cy, r*0.78, c)
+
+	if trayIconExt() == ".ico" {
+		return encodeICO(dst, icoSizes)
+	}
+	return encodePNG(resizeSquare(dst, pngIconSize))
+}
+
+// fillCircle 绘制实心圆
+func fillCircle(img *image.NRGBA, cx, cy, r float64, c color.Color) {
+	for y := int(cy - r); y <= int(cy+r); y++ {
+		for x := int(cx - r); x <= int(cx+r); x++ {
+			dx, dy := float64(x)+0.5-cx, float64(y)+0.5-cy
+			if dx*dx+dy*dy <= r*r {
+				img.Set(x, y, c)
+			}
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IPC 命令（每条命令一行）
+const (
+	ipcOpenURL    = "OPEN_URL"    // 打开网页，无响应
+	ipcStatus     = "STATUS"      // 返回一行 JSON 格式的运行状态
+	ipcOpenScheme = "OPEN_SCHEME" // 打开协议链接（命令后跟空格、链接，以及 Tab 和来源程序），无响应
+)
+
+var (
+	statusMu        sync.RWMutex
+	statusProviders = make(map[string]func() interface{})
+)
+
+// registerStatus 注册 IPC 状态中的一项，fn 返回值需可序列化为 JSON
+func registerStatus(name string, fn func() interface{}) {
+	statusMu.Lock()
+	statusProviders[name] = fn
+	statusMu.Unlock()
+}
+
+// collectStatus 汇总运行状态
+func collectStatus() map[string]interface{} {
+	status := map[string]interface{}{
+		"pid":     os.Getpid(),
+		"title":   config.GetTitle(),
+		"profile": config.GetActiveProfile(),
+		"url":     targetURL(),
+	}
+	statusMu.RLock()
+	defer statusMu.RUnlock()
+	for name, fn := range statusProviders {
+		status[name] = fn()
+	}
+	return status
+}
+
+// startIPCServer 启动 IPC 服务
+func startIPCServer(onOpenURL func()) error {
+	listener, err := ipcListen()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				continue
+			}
+			go handleIPCConnection(conn, onOpenURL)
+		}
+	}()
+
+	return nil
+}
+
+func handleIPCConnection(conn net.Conn, onOpenURL func()) {
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	reader := bufio.NewReader(conn)
+	cmd, err := reader.ReadString('\n')
+	if err != nil {
+		return
+	}
+
+	cmd, arg, _ := strings.Cut(strings.TrimSpace(cmd), " ")
+	switch cmd {
+	case ipcOpenURL:
+		if onOpenURL != nil {
+			onOpenURL()
+		}
+	case ipcOpenScheme:
+		link, source, _ := strings.Cut(arg, "\t")
+		go openSchemeURL(link, source)
+	case ipcStatus:
+		data, _ := json.Marshal(collectStatus())
+		conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+		conn.Write(append(data, '\n'))
+	}
+}
+
+// sendOpenURLCommand 向已运行的实例发送打开 URL 命令
+func sendOpenURLCommand() error {
+	return sendIPCCommand(ipcOpenURL)
+}
+
+// sendSchemeURL 把协议链接及打开它的程序名称转交给已运行的实例
+func sendSchemeURL(link, source string) error {
+	clean := strings.NewReplacer("\r", "", "\n", "", "\t", "")
+	return sendIPCCommand(ipcOpenScheme + " " + clean.Replace(link) + "\t" + clean.Replace(source))
+}
+
+// sendIPCCommand 发送一行无响应的命令
+func sendIPCCommand(line string) error {
+	conn, err := ipcDial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	conn.SetWriteDeadline(time.Now().Add(2 * time.Second))
+	_, err = conn.Write([]byte(line + "\n"))
+	return err
+}
+
+// queryStatus 获取已运行实例的状态（一行 JSON）
+func queryStatus() (string, error) {
+	conn, err := ipcDial()
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Write([]byte(ipcStatus + "\n")); err != nil {
+		return "", err
+	}
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os/exec"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// KioskConfig 看板模式：监护浏览器进程，窗口被关闭或浏览器崩溃后自动重新打开（仅托盘模式）
+type KioskConfig struct {
+	URL             string         `json:"url,omitempty"`             // 打开的网页，空则打开默认站点或当前目标地址
+	Browser         *BrowserConfig `json:"browser,omitempty"`         // 浏览器（必须指定 path，空则使用 browser 配置）
+	RestartDelay    Duration       `json:"restartDelay,omitempty"`    // 首次重新打开前的等待时间，之后每次翻倍，默认 2s
+	MaxRestartDelay Duration       `json:"maxRestartDelay,omitempty"` // 等待时间上限，默认 1m；浏览器运行超过该时长后等待时间复位
+	RestartAt       string         `json:"restartAt,omitempty"`       // 每天定时重启浏览器的时间，如 04:00
+	StopTimeout     Duration       `json:"stopTimeout,omitempty"`     // 结束浏览器时等待退出的时间，超时后强制结束，默认 5s
+}
+
+// KioskStatus 看板模式状态（IPC 状态中输出）
+type KioskStatus struct {
+	PID         int        `json:"pid,omitempty"`
+	URL         string     `json:"url,omitempty"`
+	Restarts    int        `json:"restarts"`
+	Error       string     `json:"error,omitempty"` // 上次退出或启动失败的原因
+	NextRestart *time.Time `json:"nextRestart,omitempty"`
+}
+
+type kiosk struct {
+	cfg    KioskConfig
+	ctx    context.Context // 停止监护时取消，中断等待地址可访问和重新打开前的等待
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu       sync.Mutex
+	cmd      *exec.Cmd
+	url      string
+	restarts int
+	err      error
+	next     time.Time // 等待重新打开时的下次打开时间
+}
+
+var (
+	kioskMu    sync.Mutex
+	kioskSv    *kiosk
+	kioskArmed bool // 启动流程已执行到打开网页，之后热重载可启动或停止看板模式
+)
+
+func init() {
+	registerStatus("kiosk", func() interface{} {
+		kioskMu.Lock()
+		k := kioskSv
+		kioskMu.Unlock()
+		if k == nil {
+			return nil
+		}
+		return k.Status()
+	})
+}
+
+// browser 返回看板模式使用的浏览器
+// 系统默认浏览器经 xdg-open / open / start 转交后启动程序会立即退出，无法监护，因此要求指定 path
+func (c KioskConfig) browser() (*BrowserConfig, error) {
+	b := c.Browser
+	if b == nil {
+		b = config.GetBrowser()
+	}
+	if b == nil || b.Path == "" {
+		return nil, errors.New(T("err.kiosk_browser"))
+	}
+	return b, nil
+}
+
+// target 返回要打开的网页，每次打开时重新计算（网络规则、镜像等可能已变化）
+func (c KioskConfig) target() string {
+	if c.URL != "" {
+		return c.URL
+	}
+	if s, ok := config.GetDefaultSite(); ok && s.Form == nil {
+		return s.URL
+	}
+	ensureNetwork()
+	refreshMirror()
+	return targetURL()
+}
+
+// nextClock 返回 now 之后下一次到达当天第 minute 分钟的时间
+func nextClock(minute int, now time.Time) time.Time {
+	t := time.Date(now.Year(), now.Month(), now.Day(), minute/60, minute%60, 0, 0, now.Location())
+	if !t.After(now) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+func (k *kiosk) loop() {
+	defer close(k.done)
+	restartAt := -1
+	if k.cfg.RestartAt != "" {
+		m, err := parseClock(k.cfg.RestartAt)
+		if err != nil {
+			log.Printf(T("log.kiosk_restart_at_invalid"), err)
+		} else {
+			restartAt = m
+		}
+	}
+
+	wakeHost()
+	var delay time.Duration
+	for {
+		var daily <-chan time.Time
+		var timer *time.Timer
+		if restartAt >= 0 {
+			timer = time.NewTimer(time.Until(nextClock(restartAt, time.Now())))
+			daily = timer.C
+		}
+		ranFor, err := k.launch(daily)
+		if timer != nil {
+			timer.Stop()
+		}
+		select {
+		case <-k.ctx.Done():
+			return
+		default:
+		}
+		if err == nil {
+			// 定时重启，立即重新打开
+			delay = 0
+			continue
+		}
+
+		maxDelay := k.cfg.MaxRestartDelay.Or(time.Minute)
+		if ranFor >= maxDelay {
+			delay = 0
+		}
+		if delay == 0 {
+			delay = k.cfg.RestartDelay.Or(2 * time.Second)
+		} else {
+			delay *= 2
+		}
+		if delay > maxDelay {
+			delay = maxDelay
+		}
+		log.Printf(T("log.kiosk_restart_in"), delay)
+		k.mu.Lock()
+		k.next = time.Now().Add(delay)
+		k.mu.Unlock()
+		select {
+		case <-k.ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		k.mu.Lock()
+		k.next = time.Time{}
+		k.restarts++
+		k.mu.Unlock()
+	}
+}
+
+// launch 打开浏览器并等待其退出、到达定时重启时间或监护停止
+// 浏览器自行退出或无法启动时返回原因，定时重启或停止时返回 nil
+func (k *kiosk) launch(daily <-chan time.Time) (time.Duration, error) {
+	b, err := k.cfg.browser()
+	if err != nil {
+		return 0, k.fail(err)
+	}
+	url := k.cfg.target()
+	if !waitTargetContext(k.ctx, url) {
+		if k.ctx.Err() != nil {
+			return 0, nil
+		}
+		return 0, k.fail(errors.New(T("err.kiosk_unreachable")))
+	}
+
+	cmd, err := startBrowser(url, b)
+	if err != nil {
+		return 0, k.fail(err)
+	}
+	log.Printf(T("log.kiosk_started"), url, cmd.Process.Pid)
+	started := time.Now()
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	k.mu.Lock()
+	k.cmd, k.url = cmd, url
+	k.mu.Unlock()
+	defer func() {
+		k.mu.Lock()
+		k.cmd = nil
+		k.mu.Unlock()
+	}()
+
+	select {
+	case err := <-exited:
+		if err == nil {
+			err = errors.New(T("err.kiosk_closed"))
+		}
+		log.Printf(T("log.kiosk_exited"), err)
+		return time.Since(started), k.fail(err)
+	case <-daily:
+		log.Println(T("log.kiosk_daily_restart"))
+	case <-k.ctx.Done():
+	}
+	k.terminate(cmd, exited)
+	return time.Since(started), nil
+}
+
+func (k *kiosk) fail(err error) error {
+	k.mu.Lock()
+	k.err = err
+	k.mu.Unlock()
+	return err
+}
+
+// terminate 请求浏览器退出，超时后强制结束
+func (k *kiosk) terminate(cmd *exec.Cmd, exited <-chan error) {
+	log.Println(T("log.kiosk_stopping"))
+	if err := terminateProcess(cmd.Process); err != nil {
+		log.Printf(T("log.kiosk_terminate_failed"), err)
+	}
+	select {
+	case <-exited:
+	case <-time.After(k.cfg.StopTimeout.Or(5 * time.Second)):
+		killProcess(cmd.Process)
+		select {
+		case <-exited:
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+// Status 返回当前状态
+func (k *kiosk) Status() KioskStatus {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	st := KioskStatus{URL: k.url, Restarts: k.restarts}
+	if k.cmd != nil {
+		st.PID = k.cmd.Process.Pid
+	}
+	if k.err != nil {
+		st.Error = k.err.Error()
+	}
+	if !k.next.IsZero() {
+		next := k.next
+		st.NextRestart = &next
+	}
+	return st
+}
+
+// applyKiosk 按配置启动、重启或停止看板模式（配置变化时浏览器会重新打开）
+func applyKiosk(cfg *KioskConfig) {
+	kioskMu.Lock()
+	old := kioskSv
+	if old == nil && cfg == nil || old != nil && cfg != nil && reflect.DeepEqual(old.cfg, *cfg) {
+		kioskMu.Unlock()
+		return
+	}
+	var k *kiosk
+	if cfg != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		k = &kiosk{cfg: *cfg, ctx: ctx, cancel: cancel, done: make(chan struct{})}
+	}
+	kioskSv = k
+	kioskMu.Unlock()
+
+	if old != nil {
+		old.cancel()
+		<-old.done
+	}
+	if k != nil {
+		log.Println(T("log.kiosk_enabled"))
+		go k.loop()
+	}
+}
+
+// startKiosk 启动时调用：配置了看板模式时打开并监护浏览器，返回是否已启用
+func startKiosk() bool {
+	kioskMu.Lock()
+	kioskArmed = true
+	kioskMu.Unlock()
+	cfg := config.GetKiosk()
+	if cfg == nil {
+		return false
+	}
+	applyKiosk(cfg)
+	return true
+}
+
+// kioskStarted 启动流程是否已交由看板模式处理（此前的热重载不启动浏览器，避免早于后端进程就绪）
+func kioskStarted() bool {
+	kioskMu.Lock()
+	defer kioskMu.Unlock()
+	return kioskArmed
+}
+
+// stopKiosk 停止看板模式并结束浏览器（退出时调用）
+func stopKiosk() {
+	kioskMu.Lock()
+	kioskArmed = false
+	kioskMu.Unlock()
+	applyKiosk(nil)
+}
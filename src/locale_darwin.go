@@ -0,0 +1,17 @@
+//go:build darwin
+
+package main
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// platformLanguage 返回 macOS 系统区域设置（从 Finder 启动时没有 LANG 环境变量）
+func platformLanguage() string {
+	out, err := exec.Command("defaults", "read", "-g", "AppleLocale").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,8 @@
+//go:build linux
+
+package main
+
+// platformLanguage Linux 下语言完全由 LANG / LC_* 环境变量决定
+func platformLanguage() string {
+	return ""
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package main
+
+import "golang.org/x/sys/windows"
+
+// platformLanguage 返回 Windows 界面语言（如 zh-CN）
+func platformLanguage() string {
+	langs, err := windows.GetUserPreferredUILanguages(windows.MUI_LANGUAGE_NAME)
+	if err != nil || len(langs) == 0 {
+		return ""
+	}
+	return langs[0]
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"sync"
+)
+
+// logWriter 将子进程输出按行写入日志
+type logWriter struct {
+	prefix string
+	mu     sync.Mutex
+	buf    []byte
+}
+
+func newLogWriter(prefix string) *logWriter {
+	return &logWriter{prefix: prefix}
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.output(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush 输出缓冲中剩余的不完整行
+func (w *logWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		w.output(w.buf)
+		w.buf = nil
+	}
+}
+
+func (w *logWriter) output(line []byte) {
+	line = bytes.TrimRight(line, "\r")
+	if len(line) == 0 {
+		return
+	}
+	log.Printf("[%s] %s", w.prefix, line)
+}
@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"reflect"
+	"syscall"
 
 	"github.com/energye/systray"
 )
@@ -15,27 +18,107 @@ import (
 var embeddedIcon []byte
 
 var (
-	trayMode     = flag.Bool("tray", false, "强制托盘模式")
-	openOnce     = flag.Bool("open", false, "仅打开浏览器并退出")
-	staticConfig = flag.Bool("static", false, "启用静态配置（不生成外部配置，同时不监控、采用外部配置）")
+	trayMode     = flag.Bool("tray", false, T("flag.tray"))
+	openOnce     = flag.Bool("open", false, T("flag.open"))
+	staticConfig = flag.Bool("static", false, T("flag.static"))
+	profileName  = flag.String("profile", "", T("flag.profile"))
+	showStatus   = flag.Bool("status", false, T("flag.status"))
+	schemeLink   = flag.String("url", "", T("flag.url"))
 )
 
 var (
-	config   *Config
-	menuAuto *systray.MenuItem
+	config    *Config
+	menuAuto  *systray.MenuItem
+	menuBuilt menuLayout // 当前菜单对应的配置，用于判断是否需要重建菜单
+
+	menuProfiles map[string]*systray.MenuItem
+
+	menuSidecarState   *systray.MenuItem
+	menuSidecarStart   *systray.MenuItem
+	menuSidecarStop    *systray.MenuItem
+	menuSidecarRestart *systray.MenuItem
+
+	menuScheduleItems []*systray.MenuItem // 即将运行的定时任务
+	menuSchedulePause *systray.MenuItem
+
+	iconWatch *iconWatcher // 监控外置图标文件，变化时更新托盘图标
 )
 
+// menuLayout 决定菜单结构的配置项，变化时需要重建菜单
+type menuLayout struct {
+	Sites    []Site
+	Profiles []Profile
+	Menu     []MenuEntry
+	Language string
+	Sidecar  bool
+	Wake     bool
+	Schedule int // 托盘菜单中即将运行的任务项数
+}
+
+func currentMenuLayout(c *Config) menuLayout {
+	l := menuLayout{
+		Sites:    c.GetSites(),
+		Profiles: c.GetProfiles(),
+		Menu:     c.GetMenu(),
+		Language: currentLanguage(),
+		Sidecar:  c.GetSidecar() != nil,
+		Wake:     c.GetWakeOnLan() != nil,
+		Schedule: len(c.GetSchedules()),
+	}
+	if l.Schedule > scheduleMenuSlots {
+		l.Schedule = scheduleMenuSlots
+	}
+	return l
+}
+
 func main() {
 	flag.Parse()
 
+	// 子命令
+	if flag.NArg() > 0 {
+		switch flag.Arg(0) {
+		case "cert":
+			os.Exit(runCertCommand(flag.Args()[1:]))
+		case "scheme":
+			os.Exit(runSchemeCommand(flag.Args()[1:]))
+		case "router":
+			os.Exit(runRouterCommand(flag.Args()[1:]))
+		default:
+			fmt.Println(T("console.unknown_command", flag.Arg(0)))
+			os.Exit(2)
+		}
+	}
+
+	// 查询已运行实例的状态
+	if *showStatus {
+		status, err := queryStatus()
+		if err != nil {
+			fmt.Println(T("console.status_failed", err))
+			os.Exit(1)
+		}
+		fmt.Println(status)
+		return
+	}
+
 	// 单例检查 - 防止程序重复运行
 	singleton, err := NewSingleton("WebLauncher_SingleInstance")
 	if err != nil {
-		// 程序已在运行，尝试通知它打开 URL
-		if sendErr := sendOpenURLCommand(); sendErr != nil {
-			fmt.Println("程序已在运行，但无法通知打开 URL:", sendErr)
+		// 程序已在运行，尝试通知它打开 URL（或转交协议链接）
+		var sendErr error
+		if *schemeLink != "" {
+			sendErr = sendSchemeURL(*schemeLink, launcherApp())
 		} else {
-			fmt.Println("程序已在运行，已通知打开 URL")
+			sendErr = sendOpenURLCommand()
+		}
+		if sendErr != nil {
+			fmt.Println(T("console.notify_failed", sendErr))
+			notifyWait(Notification{
+				Title:   T("notify.ipc_error"),
+				Body:    T("console.notify_failed", sendErr),
+				Urgency: urgencyCritical,
+			})
+		} else {
+			fmt.Println(T("console.notified"))
 		}
 		os.Exit(0)
 	}
@@ -43,7 +126,12 @@ func main() {
 
 	config, err = LoadConfig(*staticConfig)
 	if err != nil {
-		fmt.Println("加载配置失败:", err)
+		fmt.Println(T("console.load_config_failed", err))
+		notifyWait(Notification{
+			Title:   T("notify.config_error"),
+			Body:    T("console.load_config_failed", err),
+			Urgency: urgencyCritical,
+		})
 		os.Exit(1)
 	}
 
@@ -51,14 +139,64 @@ func main() {
 	logFile := filepath.Join(DataDir, "app.log")
 	f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "警告：无法打开日志文件: %v\n", err)
+		fmt.Fprintln(os.Stderr, T("console.log_open_failed", err))
 	} else {
 		log.SetOutput(f)
 		log.SetFlags(log.LstdFlags | log.Lshortfile)
-		log.Printf("数据目录: %s", DataDir)
+		log.Printf(T("log.data_dir"), DataDir)
+	}
+
+	// 界面语言：数据目录下的语言包可覆盖或扩展内嵌语言包
+	if DataDir != "" {
+		loadLocaleDir(filepath.Join(DataDir, "locales"))
+	}
+	log.Printf(T("log.language"), setLanguage(config.GetLanguage()))
+
+	// 路由模式：作为默认浏览器被调用且没有运行中的实例时，只分发链接，不启动托盘
+	if isWebURL(*schemeLink) {
+		if err := dispatchURL(*schemeLink, launcherApp()); err != nil {
+			fmt.Println(T("console.router_failed", err))
+			log.Printf(T("log.router_failed"), err)
+		}
+		return
+	}
+
+	// 命令行指定环境（需在启动静态站点、隧道和后端进程前切换，它们使用当前环境的 url）
+	if *profileName != "" {
+		if err := config.SetActiveProfile(*profileName); err != nil {
+			fmt.Println(T("console.profile_switch_failed", err))
+			log.Printf(T("log.profile_switch_failed"), err)
+		}
+	}
+
+	// 本地服务 HTTPS（需在启动本地服务前设置）
+	if err := applyTLS(config.GetTLS()); err != nil {
+		fmt.Println(T("console.tls_failed", err))
+		reportConfigError(T("console.tls_failed"), err)
+	}
+
+	// 静态站点模式：启动本地服务
+	if err := applyStatic(config.GetStatic()); err != nil {
+		fmt.Println(T("console.static_failed", err))
+		reportConfigError(T("console.static_failed"), err)
+	}
+	if err := applyProxy(config.GetProxy()); err != nil {
+		fmt.Println(T("console.proxy_failed", err))
+		reportConfigError(T("console.proxy_failed"), err)
+	}
+
+	// SSH 隧道（在打开网页前建立）
+	if err := applyTunnel(config.GetTunnel()); err != nil {
+		fmt.Println(T("console.tunnel_failed", err))
+		reportConfigError(T("console.tunnel_failed"), err)
 	}
+	defer stopTunnel()
 
-	// 命令行覆盖
+	// 本地后端进程（在打开网页前启动）
+	startSidecar()
+	defer stopSidecar()
+
+	// 命令行覆盖（托盘模式开关）
 	if *trayMode {
 		config.TrayMode = true
 	}
@@ -68,20 +206,42 @@ func main() {
 
 	// 非托盘模式
 	if !config.TrayMode {
-		openBrowser(config.GetURL())
+		waitSidecar()
+		openStartup()
+		// 本地服务和后端进程需要保持运行，直到收到退出信号
+		if localServer.Running() || currentSidecar() != nil || tunnelURL() != "" {
+			fmt.Println(T("console.serving", targetURL()))
+			sig := make(chan os.Signal, 1)
+			signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+			<-sig
+			localServer.Stop()
+		}
 		return
 	}
 
-	// 应用自启设置
-	config.applyAutoStart()
+	// 应用自启设置和自定义协议
+	if err := config.applyAutoStart(); err != nil {
+		reportAutoStartError(err)
+	}
+	if err := applyScheme(config.GetScheme()); err != nil {
+		reportConfigError(T("console.scheme_failed"), err)
+	}
+	listenURLEvents()
 
 	// 启动 IPC 服务（在 systray 之前启动，以便接收新实例的命令）
 	go func() {
 		// 等待配置加载完成（main 函数中已加载）
 		if err := startIPCServer(func() {
-			openBrowser(config.GetURL())
+			openDefault()
 		}); err != nil {
-			fmt.Println("IPC 服务启动失败:", err)
+			fmt.Println(T("console.ipc_failed", err))
+			log.Print(T("console.ipc_failed", err))
+			notify(Notification{
+				Tag:     "ipc",
+				Title:   T("notify.ipc_error"),
+				Body:    T("console.ipc_failed", err),
+				Urgency: urgencyCritical,
+			})
 		}
 	}()
 
@@ -97,29 +257,203 @@ func main() {
 func onReady() {
 	// 设置图标（读取外置或内嵌）
 	// 实际项目中应将内嵌图标转为 []byte 传入
-	systray.SetIcon(getIconData())
-	systray.SetTitle(config.GetTitle())
-	systray.SetTooltip(config.GetTitle())
+	if !config.Static {
+		w, err := newIconWatcher(func() {
+			log.Println(T("log.icon_changed"))
+			updateTrayIcon()
+		})
+		if err != nil {
+			log.Printf(T("log.icon_watch_failed"), err)
+		} else {
+			iconWatch = w
+		}
+	}
+	enableTooltip()
+	refreshTray()
 	// systray.SetTemplateIcon(getIconData(), getIconData()) // 模板图标支持
-	// 双击托盘图标打开网页
+	// 双击托盘图标打开默认站点
 	systray.SetOnDClick(func(menu systray.IMenu) {
-		openBrowser(config.GetURL())
+		go openDefault()
+	})
+
+	buildMenu()
+
+	// 配置变更回调（热重载后更新 UI）
+	config.SetOnChange(func(c *Config) {
+		setLanguage(c.GetLanguage())
+		if err := applyTLS(c.GetTLS()); err != nil {
+			reportConfigError(T("console.tls_failed"), err)
+		}
+		if err := applyStatic(c.GetStatic()); err != nil {
+			reportConfigError(T("console.static_failed"), err)
+		}
+		if err := applyProxy(c.GetProxy()); err != nil {
+			reportConfigError(T("console.proxy_failed"), err)
+		}
+		if err := applyTunnel(c.GetTunnel()); err != nil {
+			reportConfigError(T("console.tunnel_failed"), err)
+		}
+		applySidecar(c.GetSidecar())
+		applyNetworkRules(c.GetNetworkRules())
+		applyHealth(c.GetHealth())
+		applySchedules(c.GetSchedules())
+		if kioskStarted() {
+			applyKiosk(c.GetKiosk())
+		}
+		if err := applyScheme(c.GetScheme()); err != nil {
+			reportConfigError(T("console.scheme_failed"), err)
+		}
+		refreshTray()
+		// 站点、环境列表或界面语言变化时重建菜单
+		if !reflect.DeepEqual(menuBuilt, currentMenuLayout(c)) {
+			systray.ResetMenu()
+			buildMenu()
+			return
+		}
+		if c.GetAutoStart() {
+			menuAuto.Check()
+		} else {
+			menuAuto.Uncheck()
+		}
+		updateProfileChecks()
 	})
 
-	// 菜单
-	menuOpen := systray.AddMenuItem("打开网页", "Open URL")
+	// 网络规则、后台健康检查和定时任务
+	applyNetworkRules(config.GetNetworkRules())
+	applyHealth(config.GetHealth())
+	applySchedules(config.GetSchedules())
+
+	// 启动时自动打开浏览器（等待后端进程就绪，不阻塞托盘）
+	go func() {
+		waitSidecar()
+		openStartup()
+	}()
+}
+
+// openStartup 启动时打开网页：由协议链接启动时打开链接对应的网页，
+// 托盘模式下配置了 kiosk 时由看板模式打开并监护浏览器，配置了 startupUrls 时依次打开，否则打开默认站点
+func openStartup() {
+	if *schemeLink != "" {
+		openSchemeURL(*schemeLink, launcherApp())
+		return
+	}
+	if config.TrayMode && startKiosk() {
+		return
+	}
+	if config.TrayMode && openStartupURLs() {
+		return
+	}
+	openDefault()
+}
+
+// refreshTray 更新托盘标题、提示和图标（环境切换、配置或图标文件变化后调用）
+func refreshTray() {
+	updateTrayIcon()
+	if iconWatch != nil {
+		iconWatch.SetPath(config.GetIcon())
+	}
+	systray.SetTitle(config.GetTrayTitle())
+	updateTooltip()
+}
+
+// buildMenu 构建托盘菜单（热重载时可在 ResetMenu 后重新调用）
+func buildMenu() {
+	menuBuilt = currentMenuLayout(config)
+
+	menuOpen := systray.AddMenuItem(T("menu.open"), T("menu.open.tip"))
 	menuOpen.Click(func() {
-		openBrowser(config.GetURL())
+		go openDefault()
 	})
 
-	menuAuto = systray.AddMenuItemCheckbox("开机自启", "Auto start on boot", config.GetAutoStart())
+	if menuBuilt.Wake {
+		menuWake := systray.AddMenuItem(T("menu.wake"), T("menu.wake.tip"))
+		menuWake.Click(func() {
+			go wakeHost()
+		})
+	}
+
+	// 站点子菜单
+	if len(menuBuilt.Sites) > 0 {
+		menuSiteRoot := systray.AddMenuItem(T("menu.sites"), T("menu.sites.tip"))
+		for _, site := range menuBuilt.Sites {
+			target := site.Target()
+			if target == "" {
+				continue
+			}
+			site := site
+			title := site.Title
+			if title == "" {
+				title = target
+			}
+			item := menuSiteRoot.AddSubMenuItem(title, target)
+			if icon := siteIconData(site); icon != nil {
+				item.SetIcon(icon)
+			}
+			item.Click(func() {
+				openSite(site)
+			})
+		}
+	}
+
+	// 环境子菜单（单选）
+	menuProfiles = make(map[string]*systray.MenuItem)
+	if len(menuBuilt.Profiles) > 0 {
+		menuProfileRoot := systray.AddMenuItem(T("menu.profiles"), T("menu.profiles.tip"))
+		active := config.GetActiveProfile()
+		for _, p := range menuBuilt.Profiles {
+			name := p.Name
+			item := menuProfileRoot.AddSubMenuItemCheckbox(name, p.URL, name == active)
+			item.Click(func() {
+				if err := config.SetActiveProfile(name); err != nil {
+					log.Printf(T("log.profile_switch_failed"), err)
+					return
+				}
+				log.Printf(T("log.profile_switched"), name)
+				updateProfileChecks()
+				refreshTray()
+				// 隧道转发目标可能随环境的 url 变化
+				go func() {
+					if err := applyTunnel(config.GetTunnel()); err != nil {
+						reportConfigError(T("console.tunnel_failed"), err)
+					}
+				}()
+			})
+			menuProfiles[name] = item
+		}
+	}
+
+	// 后端进程子菜单
+	menuSidecarState = nil
+	if menuBuilt.Sidecar {
+		addSidecarMenu()
+	}
+
+	// 定时任务子菜单
+	menuSchedulePause = nil
+	if menuBuilt.Schedule > 0 {
+		addScheduleMenu(menuBuilt.Schedule)
+	}
+
+	// 自定义菜单项
+	if len(menuBuilt.Menu) > 0 {
+		systray.AddSeparator()
+		addMenuEntries(nil, menuBuilt.Menu)
+		systray.AddSeparator()
+	}
+
+	menuAuto = systray.AddMenuItemCheckbox(T("menu.autostart"), T("menu.autostart.tip"), config.GetAutoStart())
 	if config.Static { // 静态模式不允许修改自启设置
 		menuAuto.Disable()
 	}
 	menuAuto.Click(func() {
 		newState := !config.GetAutoStart()
 		config.SetAutoStart(newState)
-		config.applyAutoStart()
+		if err := config.applyAutoStart(); err != nil {
+			// 未生效时恢复原设置
+			reportAutoStartError(err)
+			config.SetAutoStart(!newState)
+			newState = !newState
+		}
 		if newState {
 			menuAuto.Check()
 		} else {
@@ -128,45 +462,178 @@ func onReady() {
 	})
 
 	systray.AddSeparator()
-	menuReload := systray.AddMenuItem("重载配置", "Reload config.json")
+	menuReload := systray.AddMenuItem(T("menu.reload"), T("menu.reload.tip"))
 	menuReload.Click(func() {
 		// 手动触发重载逻辑，实际已由 fsnotify 处理，可能也有需求
 		// TODO: 可能需要增加防抖，避免重复触发
 	})
 
-	menuQuit := systray.AddMenuItem("退出", "Quit")
+	menuQuit := systray.AddMenuItem(T("menu.quit"), T("menu.quit.tip"))
 	menuQuit.Click(func() {
 		systray.Quit()
 	})
+}
 
-	// 配置变更回调（热重载后更新 UI）
-	config.SetOnChange(func(c *Config) {
-		systray.SetTitle(c.GetTitle())
-		systray.SetTooltip(c.GetTitle())
-		if c.GetAutoStart() {
-			menuAuto.Check()
+// updateProfileChecks 同步环境菜单的选中状态
+func updateProfileChecks() {
+	active := config.GetActiveProfile()
+	for name, item := range menuProfiles {
+		if name == active {
+			item.Check()
 		} else {
-			menuAuto.Uncheck()
+			item.Uncheck()
+		}
+	}
+}
+
+// addSidecarMenu 添加后端进程子菜单：当前状态及启动、停止、重启
+func addSidecarMenu() {
+	root := systray.AddMenuItem(T("menu.sidecar"), T("menu.sidecar.tip"))
+	menuSidecarState = root.AddSubMenuItem("", "")
+	menuSidecarState.Disable()
+	menuSidecarStart = root.AddSubMenuItem(T("menu.sidecar.start"), T("menu.sidecar.start.tip"))
+	menuSidecarStart.Click(func() {
+		if s := currentSidecar(); s != nil {
+			if err := s.Start(); err != nil {
+				log.Print(err)
+			}
+		}
+	})
+	menuSidecarStop = root.AddSubMenuItem(T("menu.sidecar.stop"), T("menu.sidecar.stop.tip"))
+	menuSidecarStop.Click(func() {
+		if s := currentSidecar(); s != nil {
+			go s.Stop()
+		}
+	})
+	menuSidecarRestart = root.AddSubMenuItem(T("menu.sidecar.restart"), T("menu.sidecar.restart.tip"))
+	menuSidecarRestart.Click(func() {
+		if s := currentSidecar(); s != nil {
+			go func() {
+				if err := s.Restart(); err != nil {
+					log.Print(err)
+				}
+			}()
 		}
 	})
+	updateSidecarMenu()
+}
+
+// updateSidecarMenu 同步后端进程子菜单的状态文字和可用项
+func updateSidecarMenu() {
+	s := currentSidecar()
+	if s == nil || menuSidecarState == nil {
+		return
+	}
+	st := s.Status()
+	text := T("menu.sidecar.state." + st.State)
+	switch {
+	case st.PID > 0:
+		text += " " + T("menu.sidecar.pid", st.PID)
+	case st.ExitCode != nil:
+		text += " " + T("menu.sidecar.exit_code", *st.ExitCode)
+	}
+	menuSidecarState.SetTitle(text)
+
+	running := st.PID > 0
+	setEnabled(menuSidecarStart, !running)
+	setEnabled(menuSidecarStop, running || st.State == sidecarBackoff)
+	setEnabled(menuSidecarRestart, running)
+}
+
+// addScheduleMenu 添加定时任务子菜单：即将运行的任务及暂停开关
+func addScheduleMenu(slots int) {
+	root := systray.AddMenuItem(T("menu.schedule"), T("menu.schedule.tip"))
+	menuScheduleItems = nil
+	for i := 0; i < slots; i++ {
+		item := root.AddSubMenuItem("", "")
+		item.Disable()
+		menuScheduleItems = append(menuScheduleItems, item)
+	}
+	menuSchedulePause = root.AddSubMenuItemCheckbox(T("menu.schedule.pause"), T("menu.schedule.pause.tip"), schedulesPaused())
+	menuSchedulePause.Click(func() {
+		setSchedulesPaused(!schedulesPaused())
+	})
+	updateScheduleMenu()
+}
+
+// updateScheduleMenu 同步即将运行的任务列表和暂停状态
+func updateScheduleMenu() {
+	s := currentScheduler()
+	if s == nil || menuSchedulePause == nil || !trayReady() {
+		return
+	}
+	upcoming := s.Upcoming(len(menuScheduleItems))
+	for i, item := range menuScheduleItems {
+		switch {
+		case i < len(upcoming):
+			item.SetTitle(T("menu.schedule.item", upcoming[i].Next.Format("01-02 15:04"), upcoming[i].Name))
+			item.Show()
+		case i == 0:
+			item.SetTitle(T("menu.schedule.none"))
+			item.Show()
+		default:
+			item.Hide()
+		}
+	}
+	if schedulesPaused() {
+		menuSchedulePause.Check()
+	} else {
+		menuSchedulePause.Uncheck()
+	}
+}
 
-	// 启动时自动打开浏览器
-	openBrowser(config.GetURL())
+func setEnabled(item *systray.MenuItem, enabled bool) {
+	if enabled {
+		item.Enable()
+	} else {
+		item.Disable()
+	}
 }
 
 func onExit() {
 	config.StopWatching()
+	stopHealth()
+	stopSchedules()
+	stopKiosk()
+	stopTunnel()
+	localServer.Stop()
+	stopSidecar()
+	if iconWatch != nil {
+		iconWatch.Close()
+	}
 }
 
-// getIconData 优先读取外置图标，否则返回内嵌字节
+// updateTrayIcon 设置托盘图标，健康检查异常时在右下角叠加状态标记
+func updateTrayIcon() {
+	if !trayReady() {
+		return
+	}
+	data := getIconData()
+	if c, ok := healthBadge(); ok {
+		badged, err := badgeIcon(data, c)
+		if err != nil {
+			log.Printf(T("log.icon_badge_failed"), err)
+		} else {
+			data = badged
+		}
+	}
+	systray.SetIcon(data)
+}
+
+// getIconData 优先读取外置图标，否则返回内嵌图标（均转换为当前平台托盘需要的格式）
 func getIconData() []byte {
 	// 如果 config.Icon 指定了外置路径，尝试读取
-	if config.GetIcon() != "" {
-		data, err := os.ReadFile(config.GetIcon())
+	if path := config.GetIcon(); path != "" {
+		data, err := loadTrayIcon(path)
 		if err == nil {
 			return data
 		}
-		fmt.Fprintf(os.Stderr, "警告: 无法读取外置图标 %s: %v，使用内嵌图标\n", config.GetIcon(), err)
+		log.Printf(T("log.icon_read_failed"), path, err)
+	}
+	data, err := normalizeIcon(embeddedIcon)
+	if err != nil {
+		log.Printf(T("log.icon_embedded_failed"), err)
+		return embeddedIcon
 	}
-	return embeddedIcon
+	return data
 }
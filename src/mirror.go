@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// MirrorConfig 备用地址：打开前依次探测 url 和备用地址，使用第一个可访问的
+type MirrorConfig struct {
+	URLs    []string `json:"urls"`              // 备用地址，按顺序排在 url 之后
+	Method  string   `json:"method,omitempty"`  // 探测方式：head（默认）、get 或 tcp
+	Timeout Duration `json:"timeout,omitempty"` // 单个地址探测超时，默认 3s
+	Reprobe Duration `json:"reprobe,omitempty"` // 选择结果的有效期，过期后打开网页时重新探测，默认 10m
+}
+
+// mirrorChoice 选择结果，保存到数据目录的 mirror.json，重启后在有效期内直接使用
+type mirrorChoice struct {
+	Candidates []string  `json:"candidates"`
+	URL        string    `json:"url"`
+	Time       time.Time `json:"time"`
+}
+
+var (
+	mirrorMu     sync.Mutex
+	mirrorChosen *mirrorChoice
+	mirrorLoaded bool
+)
+
+// mirrorCandidates 返回当前的候选地址（当前环境的 url 在前），未配置备用地址时返回 nil
+func mirrorCandidates() []string {
+	cfg := config.GetMirrors()
+	if cfg == nil || len(cfg.URLs) == 0 {
+		return nil
+	}
+	candidates := []string{config.GetURL()}
+	for _, u := range cfg.URLs {
+		if u != "" && u != candidates[0] {
+			candidates = append(candidates, u)
+		}
+	}
+	return candidates
+}
+
+// mirrorURL 返回已选择的地址，候选地址变化（如切换环境）后返回空
+func mirrorURL() string {
+	candidates := mirrorCandidates()
+	if candidates == nil {
+		return ""
+	}
+	mirrorMu.Lock()
+	defer mirrorMu.Unlock()
+	loadMirrorChoiceLocked()
+	if mirrorChosen == nil || !reflect.DeepEqual(mirrorChosen.Candidates, candidates) {
+		return ""
+	}
+	return mirrorChosen.URL
+}
+
+// refreshMirror 选择结果过期或候选地址变化时重新探测（打开网页前调用）
+func refreshMirror() {
+	cfg := config.GetMirrors()
+	candidates := mirrorCandidates()
+	// 静态站点、认证代理、隧道或已匹配的网络规则优先于备用地址，此时无需探测
+	if candidates == nil || staticURL() != "" || proxyURL() != "" || tunnelURL() != "" || networkURL() != "" {
+		setTrayStatus("mirror", "")
+		return
+	}
+
+	mirrorMu.Lock()
+	loadMirrorChoiceLocked()
+	prev := mirrorChosen
+	mirrorMu.Unlock()
+	if prev != nil && reflect.DeepEqual(prev.Candidates, candidates) &&
+		time.Since(prev.Time) < cfg.Reprobe.Or(10*time.Minute) {
+		setTrayStatus("mirror", T("tray.mirror", prev.URL))
+		return
+	}
+
+	chosen := probeMirrors(context.Background(), candidates, *cfg)
+	mirrorMu.Lock()
+	defer mirrorMu.Unlock()
+	if chosen == "" {
+		// 全部不可访问时使用主地址，下次打开时重新探测
+		log.Printf(T("log.mirror_none"), len(candidates))
+		mirrorChosen = nil
+		setTrayStatus("mirror", "")
+		return
+	}
+	if prev == nil || prev.URL != chosen {
+		log.Printf(T("log.mirror_selected"), chosen)
+	}
+	mirrorChosen = &mirrorChoice{Candidates: candidates, URL: chosen, Time: time.Now()}
+	saveMirrorChoiceLocked()
+	setTrayStatus("mirror", T("tray.mirror", chosen))
+}
+
+// probeMirrors 并发探测所有候选地址，返回排在最前面的可访问地址
+func probeMirrors(ctx context.Context, candidates []string, cfg MirrorConfig) string {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Or(3*time.Second))
+	defer cancel()
+
+	results := make([]chan bool, len(candidates))
+	for i, candidate := range candidates {
+		ch := make(chan bool, 1)
+		results[i] = ch
+		go func(candidate string) {
+			u, err := url.Parse(candidate)
+			if err == nil && u.Host != "" {
+				err = checkURL(ctx, u, cfg.Method)
+			}
+			// 已选出更靠前的地址时其余探测会被取消，不记录
+			if err != nil && !errors.Is(err, context.Canceled) {
+				log.Printf(T("log.mirror_probe_failed"), candidate, err)
+			}
+			ch <- err == nil
+		}(candidate)
+	}
+	for i, ch := range results {
+		if <-ch {
+			return candidates[i]
+		}
+	}
+	return ""
+}
+
+func mirrorChoicePath() string {
+	if DataDir == "" || config.Static {
+		return ""
+	}
+	return filepath.Join(DataDir, "mirror.json")
+}
+
+// loadMirrorChoiceLocked 首次使用时读取上次的选择结果（调用方负责加锁）
+func loadMirrorChoiceLocked() {
+	if mirrorLoaded {
+		return
+	}
+	mirrorLoaded = true
+	path := mirrorChoicePath()
+	if path == "" {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	var c mirrorChoice
+	if err := json.Unmarshal(data, &c); err == nil && c.URL != "" {
+		mirrorChosen = &c
+	}
+}
+
+// saveMirrorChoiceLocked 保存选择结果（调用方负责加锁）
+func saveMirrorChoiceLocked() {
+	path := mirrorChoicePath()
+	if path == "" || mirrorChosen == nil {
+		return
+	}
+	data, _ := json.MarshalIndent(mirrorChosen, "", "  ")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		log.Printf(T("log.mirror_save_failed"), err)
+	}
+}
+
+func init() {
+	registerStatus("mirror", func() interface{} {
+		if mirrorCandidates() == nil {
+			return nil
+		}
+		mirrorMu.Lock()
+		defer mirrorMu.Unlock()
+		return mirrorChosen
+	})
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NetworkRule 按网络环境选择地址（如在公司内网时使用内网地址）
+// 所有已配置的条件都满足时规则匹配，按顺序使用第一条匹配的规则，没有条件的规则总是匹配
+type NetworkRule struct {
+	Name       string `json:"name"`
+	URL        string `json:"url"`
+	Resolves   string `json:"resolves,omitempty"`   // 域名可以解析（如只有内网 DNS 能解析的域名）
+	Subnet     string `json:"subnet,omitempty"`     // 本机某个网卡的地址位于该网段（CIDR，如 10.0.0.0/8）
+	GatewayMAC string `json:"gatewayMac,omitempty"` // 默认网关的 MAC 地址
+	Reachable  string `json:"reachable,omitempty"`  // 可以连接的 host:port
+}
+
+// 网络变化检测间隔和单项检测超时
+const (
+	networkPollInterval = 5 * time.Second
+	networkCheckTimeout = 2 * time.Second
+)
+
+// networkMatch 规则匹配结果
+type networkMatch struct {
+	Rule      string    `json:"rule,omitempty"` // 匹配的规则名称，没有匹配时为空
+	URL       string    `json:"url,omitempty"`
+	Evaluated time.Time `json:"evaluated"`
+}
+
+var (
+	networkMu     sync.Mutex
+	networkResult *networkMatch
+	networkRules  []NetworkRule // 上次匹配时使用的规则
+	networkCancel context.CancelFunc
+)
+
+// networkURL 返回匹配规则的地址，没有匹配或规则已变化时返回空
+func networkURL() string {
+	rules := config.GetNetworkRules()
+	if len(rules) == 0 {
+		return ""
+	}
+	networkMu.Lock()
+	defer networkMu.Unlock()
+	if networkResult == nil || !reflect.DeepEqual(rules, networkRules) {
+		return ""
+	}
+	return networkResult.URL
+}
+
+// evaluateNetwork 按顺序检查规则并记录结果
+func evaluateNetwork() {
+	rules := config.GetNetworkRules()
+	match := &networkMatch{Evaluated: time.Now()}
+	for _, r := range rules {
+		if r.URL != "" && ruleMatches(r) {
+			match.Rule = r.Name
+			match.URL = r.URL
+			break
+		}
+	}
+
+	networkMu.Lock()
+	prev := networkResult
+	networkResult = match
+	networkRules = rules
+	networkMu.Unlock()
+
+	if prev == nil || prev.Rule != match.Rule || prev.URL != match.URL {
+		if match.Rule != "" || match.URL != "" {
+			log.Printf(T("log.network_rule_matched"), match.Rule, match.URL)
+		} else if len(rules) > 0 {
+			log.Println(T("log.network_rule_none"))
+		}
+	}
+}
+
+// ensureNetwork 规则尚未匹配过或已变化时立即匹配（打开网页前调用）
+func ensureNetwork() {
+	rules := config.GetNetworkRules()
+	if len(rules) == 0 {
+		return
+	}
+	networkMu.Lock()
+	fresh := networkResult != nil && reflect.DeepEqual(rules, networkRules)
+	networkMu.Unlock()
+	if !fresh {
+		evaluateNetwork()
+	}
+}
+
+// ruleMatches 检查规则的所有条件
+func ruleMatches(r NetworkRule) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), networkCheckTimeout)
+	defer cancel()
+
+	if r.Subnet != "" && !inLocalSubnet(r.Subnet) {
+		return false
+	}
+	if r.GatewayMAC != "" {
+		mac, err := gatewayMAC()
+		if err != nil || mac != normalizeMAC(r.GatewayMAC) {
+			return false
+		}
+	}
+	if r.Resolves != "" {
+		if _, err := net.DefaultResolver.LookupHost(ctx, r.Resolves); err != nil {
+			return false
+		}
+	}
+	if r.Reachable != "" && dialTCP(ctx, r.Reachable) != nil {
+		return false
+	}
+	return true
+}
+
+// inLocalSubnet 判断本机是否有网卡地址位于指定网段
+func inLocalSubnet(cidr string) bool {
+	_, subnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		log.Printf(T("log.network_bad_subnet"), cidr, err)
+		return false
+	}
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return false
+	}
+	for _, a := range addrs {
+		if ipnet, ok := a.(*net.IPNet); ok && subnet.Contains(ipnet.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeMAC 统一 MAC 地址格式（小写、冒号分隔、每段两位）
+// macOS 的 arp 输出会省略每段开头的 0（如 0:1a:2b:3:4:5），先补齐再解析
+func normalizeMAC(mac string) string {
+	parts := strings.Split(strings.ReplaceAll(mac, "-", ":"), ":")
+	for i, p := range parts {
+		if len(p) == 1 {
+			parts[i] = "0" + p
+		}
+	}
+	if hw, err := net.ParseMAC(strings.Join(parts, ":")); err == nil {
+		return hw.String()
+	}
+	return strings.ToLower(mac)
+}
+
+// interfaceFingerprint 已启用网卡及其地址，用于检测网络变化
+func interfaceFingerprint() string {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return ""
+	}
+	var parts []string
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 {
+			continue
+		}
+		addrs, _ := iface.Addrs()
+		for _, a := range addrs {
+			parts = append(parts, iface.Name+"="+a.String())
+		}
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// applyNetworkRules 配置了规则时在后台监测网卡变化并重新匹配（启动和热重载时调用）
+func applyNetworkRules(rules []NetworkRule) {
+	networkMu.Lock()
+	if networkCancel != nil {
+		networkCancel()
+		networkCancel = nil
+	}
+	if len(rules) == 0 {
+		networkResult = nil
+		networkMu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	networkCancel = cancel
+	networkMu.Unlock()
+
+	go func() {
+		evaluateNetwork()
+		// 网卡地址指纹变化时重新匹配
+		fingerprint := interfaceFingerprint()
+		ticker := time.NewTicker(networkPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if fp := interfaceFingerprint(); fp != fingerprint {
+				fingerprint = fp
+				log.Println(T("log.network_changed"))
+				evaluateNetwork()
+			}
+		}
+	}()
+}
+
+func init() {
+	registerStatus("network", func() interface{} {
+		if len(config.GetNetworkRules()) == 0 {
+			return nil
+		}
+		networkMu.Lock()
+		defer networkMu.Unlock()
+		return networkResult
+	})
+}
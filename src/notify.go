@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sync"
+)
+
+// Notification 桌面通知（托盘程序没有控制台，重要事件通过通知告知用户）
+type Notification struct {
+	Tag     string         // 标签，相同标签的新通知替换旧通知（如健康状态），空则不替换
+	Title   string         // 标题
+	Body    string         // 正文
+	Urgency int            // 紧急程度：urgencyLow（零值）、urgencyNormal、urgencyCritical
+	Actions []NotifyAction // 通知上的按钮，第一个同时作为点击通知的默认动作
+}
+
+// NotifyAction 通知按钮
+type NotifyAction struct {
+	Label string
+	Run   func()
+}
+
+// 紧急程度（与 freedesktop 通知规范一致）
+const (
+	urgencyLow      = 0
+	urgencyNormal   = 1
+	urgencyCritical = 2
+)
+
+var notifyFailOnce sync.Once
+
+// notify 异步显示桌面通知
+func notify(n Notification) {
+	go notifyWait(n)
+}
+
+// notifyWait 显示桌面通知并等待发送完成（用于随后即退出的场景），配置关闭通知时不做任何事；显示失败只记录一次日志
+func notifyWait(n Notification) {
+	if config != nil && !config.GetNotifications() {
+		return
+	}
+	if err := showNotification(n); err != nil {
+		notifyFailOnce.Do(func() {
+			log.Printf(T("log.notify_failed"), err)
+		})
+	}
+}
+
+// notifyAppName 通知中显示的程序名称
+func notifyAppName() string {
+	if config == nil {
+		return "WebLauncher"
+	}
+	return config.GetTitle()
+}
+
+// openConfigAction 打开配置文件的通知按钮（静态配置没有外置文件时为 nil）
+func openConfigAction() []NotifyAction {
+	if config == nil || config.path == "" {
+		return nil
+	}
+	path := config.path
+	return []NotifyAction{{Label: T("notify.open_config"), Run: func() {
+		if err := openBrowser(path); err != nil {
+			log.Printf(T("log.open_failed"), err)
+		}
+	}}}
+}
+
+// reportConfigError 记录配置错误并发送通知
+func reportConfigError(format string, err error) {
+	msg := fmt.Sprintf(format, err)
+	log.Print(msg)
+	notify(Notification{
+		Tag:     "config",
+		Title:   T("notify.config_error"),
+		Body:    msg,
+		Urgency: urgencyCritical,
+		Actions: openConfigAction(),
+	})
+}
+
+// reportAutoStartError 记录开机自启设置失败并发送通知
+func reportAutoStartError(err error) {
+	log.Printf(T("log.autostart_failed"), err)
+	notify(Notification{
+		Tag:     "autostart",
+		Title:   T("notify.autostart_error"),
+		Body:    err.Error(),
+		Urgency: urgencyNormal,
+	})
+}
+
+// notifyHealth 健康状态切换通知，恢复健康时替换之前的异常通知
+func notifyHealth(from, to, errText string) {
+	body := T("notify.health_body", T("health."+from), T("health."+to))
+	if errText != "" {
+		body += "\n" + errText
+	}
+	urgency := urgencyNormal
+	if to == healthDown {
+		urgency = urgencyCritical
+	}
+	notify(Notification{
+		Tag:     "health",
+		Title:   T("notify.health", notifyAppName()),
+		Body:    body,
+		Urgency: urgency,
+		Actions: []NotifyAction{{Label: T("notify.open"), Run: openDefault}},
+	})
+}
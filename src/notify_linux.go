@@ -0,0 +1,138 @@
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	notifyService = "org.freedesktop.Notifications"
+	notifyPath    = dbus.ObjectPath("/org/freedesktop/Notifications")
+)
+
+// notifier 通过会话总线上的 org.freedesktop.Notifications 服务显示通知
+type notifier struct {
+	mu      sync.Mutex
+	conn    *dbus.Conn
+	tags    map[string]uint32         // 标签对应的通知 ID，用于替换
+	actions map[uint32][]NotifyAction // 仍在显示的通知的按钮
+}
+
+var desktopNotifier = &notifier{
+	tags:    make(map[string]uint32),
+	actions: make(map[uint32][]NotifyAction),
+}
+
+// connect 连接会话总线并订阅按钮点击和通知关闭信号，断开后重新连接（调用方负责加锁）
+func (n *notifier) connect() (*dbus.Conn, error) {
+	if n.conn != nil && n.conn.Connected() {
+		return n.conn, nil
+	}
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(notifyPath),
+		dbus.WithMatchInterface(notifyService),
+	); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	signals := make(chan *dbus.Signal, 16)
+	conn.Signal(signals)
+	go n.listen(signals)
+
+	// 旧连接上的通知 ID 已失效
+	n.conn = conn
+	n.tags = make(map[string]uint32)
+	n.actions = make(map[uint32][]NotifyAction)
+	return conn, nil
+}
+
+// listen 处理通知服务的信号，连接关闭后 signals 会被关闭
+func (n *notifier) listen(signals <-chan *dbus.Signal) {
+	for sig := range signals {
+		if len(sig.Body) < 2 {
+			continue
+		}
+		id, ok := sig.Body[0].(uint32)
+		if !ok {
+			continue
+		}
+		switch sig.Name {
+		case notifyService + ".ActionInvoked":
+			key, _ := sig.Body[1].(string)
+			n.mu.Lock()
+			actions := n.actions[id]
+			n.mu.Unlock()
+			if key == "default" && len(actions) > 0 {
+				key = "0"
+			}
+			if i, err := strconv.Atoi(key); err == nil && i >= 0 && i < len(actions) {
+				go actions[i].Run()
+			}
+		case notifyService + ".NotificationClosed":
+			n.mu.Lock()
+			delete(n.actions, id)
+			for tag, tagID := range n.tags {
+				if tagID == id {
+					delete(n.tags, tag)
+				}
+			}
+			n.mu.Unlock()
+		}
+	}
+}
+
+// showNotification 调用 Notify 方法显示通知，相同标签的通知替换旧通知
+func showNotification(msg Notification) error {
+	n := desktopNotifier
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	conn, err := n.connect()
+	if err != nil {
+		return err
+	}
+
+	var replaces uint32
+	if msg.Tag != "" {
+		replaces = n.tags[msg.Tag]
+	}
+	// 按钮为“键, 标签”成对的列表，default 键对应点击通知本身
+	var actions []string
+	for i, a := range msg.Actions {
+		actions = append(actions, strconv.Itoa(i), a.Label)
+	}
+	if len(msg.Actions) > 0 {
+		actions = append(actions, "default", msg.Actions[0].Label)
+	}
+	hints := map[string]dbus.Variant{
+		"urgency": dbus.MakeVariant(byte(msg.Urgency)),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	var id uint32
+	err = conn.Object(notifyService, notifyPath).CallWithContext(ctx, notifyService+".Notify", 0,
+		notifyAppName(), replaces, "", msg.Title, msg.Body, actions, hints, int32(-1),
+	).Store(&id)
+	if err != nil {
+		return err
+	}
+
+	delete(n.actions, replaces)
+	if len(msg.Actions) > 0 {
+		n.actions[id] = msg.Actions
+	}
+	if msg.Tag != "" {
+		n.tags[msg.Tag] = id
+	}
+	return nil
+}
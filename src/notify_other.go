@@ -0,0 +1,8 @@
+//go:build !linux
+
+package main
+
+// showNotification 暂未实现桌面通知，事件仍会写入日志
+func showNotification(msg Notification) error {
+	return nil
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// OfflineConfig 目标地址不可访问时打开本地的离线页面，页面可访问后自动跳转
+type OfflineConfig struct {
+	Title    string   `json:"title,omitempty"`    // 页面标题，默认为程序标题
+	Message  string   `json:"message,omitempty"`  // 提示文字
+	Interval Duration `json:"interval,omitempty"` // 页面检查目标地址的间隔，默认 5s
+	Port     int      `json:"port,omitempty"`     // 本地服务端口（未启动本地服务时使用，0 由系统分配）
+}
+
+// 内置离线页面模板，数据目录下的 offline.html 可覆盖
+//
+//go:embed assets/offline.html
+var offlineTemplate string
+
+// offlinePage 离线页面模板数据
+type offlinePage struct {
+	Lang        string
+	Title       string
+	Message     string
+	Target      string
+	IconURL     string
+	StatusURL   string
+	Interval    int64 // 毫秒
+	Checking    string
+	Unreachable string
+	Redirecting string
+	Retry       string
+}
+
+func init() {
+	localServer.Handle("offline", http.HandlerFunc(serveOffline))
+	localServer.Handle("status", http.HandlerFunc(serveTargetStatus))
+	localServer.Handle("icon", http.HandlerFunc(serveIcon))
+}
+
+// openOffline 启动本地服务并打开离线页面，成功时返回 true
+func openOffline(target string) bool {
+	cfg := config.GetOffline()
+	if cfg == nil {
+		return false
+	}
+	if !localServer.Running() {
+		if err := localServer.Start(cfg.Port); err != nil {
+			log.Print(err)
+			return false
+		}
+	}
+	log.Printf(T("log.offline_open"), target)
+	page := localServer.URL(internalPrefix + "offline?url=" + url.QueryEscape(target))
+	if err := openURLWith(page, config.GetBrowser()); err != nil {
+		log.Printf(T("log.open_failed"), err)
+	}
+	return true
+}
+
+// loadOfflineTemplate 优先使用数据目录下的 offline.html
+func loadOfflineTemplate() (*template.Template, error) {
+	text := offlineTemplate
+	if DataDir != "" {
+		if data, err := os.ReadFile(filepath.Join(DataDir, "offline.html")); err == nil {
+			text = string(data)
+		}
+	}
+	return template.New("offline").Parse(text)
+}
+
+func serveOffline(w http.ResponseWriter, r *http.Request) {
+	cfg := config.GetOffline()
+	if cfg == nil {
+		cfg = &OfflineConfig{}
+	}
+	tmpl, err := loadOfflineTemplate()
+	if err != nil {
+		log.Printf(T("log.offline_template_failed"), err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	target := offlineTarget(r)
+	page := offlinePage{
+		Lang:        currentLanguage(),
+		Title:       cfg.Title,
+		Message:     cfg.Message,
+		Target:      target,
+		IconURL:     internalPrefix + "icon",
+		StatusURL:   internalPrefix + "status?url=" + url.QueryEscape(target),
+		Interval:    cfg.Interval.Or(5 * time.Second).Milliseconds(),
+		Checking:    T("offline.checking"),
+		Unreachable: T("offline.unreachable"),
+		Redirecting: T("offline.redirecting"),
+		Retry:       T("offline.retry"),
+	}
+	if page.Title == "" {
+		page.Title = config.GetTitle()
+	}
+	if page.Message == "" {
+		page.Message = T("offline.message")
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := tmpl.Execute(w, page); err != nil {
+		log.Printf(T("log.offline_template_failed"), err)
+	}
+}
+
+// offlineTarget 返回请求中的地址，只接受目标地址和站点地址（避免被其他网页用来探测任意地址）
+func offlineTarget(r *http.Request) string {
+	target := targetURL()
+	want := r.URL.Query().Get("url")
+	if want == "" || want == target {
+		return target
+	}
+	for _, s := range config.GetSites() {
+		if s.Target() == want {
+			return want
+		}
+	}
+	return target
+}
+
+// serveTargetStatus 探测地址是否可访问，供离线页面轮询
+func serveTargetStatus(w http.ResponseWriter, r *http.Request) {
+	target := offlineTarget(r)
+	status := struct {
+		Reachable bool   `json:"reachable"`
+		URL       string `json:"url"`
+		Error     string `json:"error,omitempty"`
+	}{URL: target}
+
+	method := ""
+	if cfg := config.GetWait(); cfg != nil {
+		method = cfg.Method
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+	u, err := url.Parse(target)
+	if err == nil {
+		err = checkURL(ctx, u, method)
+	}
+	if err != nil {
+		status.Error = err.Error()
+	} else {
+		status.Reachable = true
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(status)
+}
+
+// serveIcon 输出程序图标
+func serveIcon(w http.ResponseWriter, r *http.Request) {
+	data := getIconData()
+	if trayIconExt() == ".ico" {
+		w.Header().Set("Content-Type", "image/x-icon")
+	} else {
+		w.Header().Set("Content-Type", "image/png")
+	}
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(data)
+}
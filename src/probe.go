@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ReadyProbe 就绪探测：TCP 端口可连接，或配置了 path 时 HTTP GET 返回 2xx/3xx
+type ReadyProbe struct {
+	Host     string   `json:"host,omitempty"`     // 默认 127.0.0.1
+	Port     int      `json:"port"`               // 探测端口
+	Path     string   `json:"path,omitempty"`     // 非空时使用 HTTP 探测，如 /health
+	Timeout  Duration `json:"timeout,omitempty"`  // 最长等待时间，默认 30s
+	Interval Duration `json:"interval,omitempty"` // 探测间隔，默认 500ms
+}
+
+// probeClient 探测使用的 HTTP 客户端，不跟随重定向（3xx 即视为服务已启动）
+var probeClient = &http.Client{
+	Transport: localTrust,
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// Check 执行一次探测
+func (p ReadyProbe) Check(ctx context.Context) error {
+	host := p.Host
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(p.Port))
+	if p.Path == "" {
+		return dialTCP(ctx, addr)
+	}
+	code, err := httpStatus(ctx, http.MethodGet, "http://"+addr+p.Path)
+	if err != nil {
+		return err
+	}
+	if code >= 400 {
+		return fmt.Errorf("HTTP %d", code)
+	}
+	return nil
+}
+
+// dialTCP 尝试建立 TCP 连接
+func dialTCP(ctx context.Context, addr string) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// httpStatus 发送请求并返回状态码
+func httpStatus(ctx context.Context, method, url string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := probeClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// waitUntil 每隔 interval 调用 check，直到成功、ctx 结束或超过 timeout
+func waitUntil(ctx context.Context, timeout, interval time.Duration, check func(context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var lastErr error
+	for {
+		attemptCtx, attemptCancel := context.WithTimeout(ctx, interval+2*time.Second)
+		lastErr = check(attemptCtx)
+		attemptCancel()
+		if lastErr == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %v", ctx.Err(), lastErr)
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package main
+
+// Profile 环境配置，用于覆盖部分基础配置（如 dev / staging / prod）
+type Profile struct {
+	Name    string         `json:"name"`
+	URL     string         `json:"url,omitempty"`     // 覆盖 url
+	Title   string         `json:"title,omitempty"`   // 覆盖托盘标题（不影响数据目录和自启项名称）
+	Icon    string         `json:"icon,omitempty"`    // 覆盖 icon
+	Browser *BrowserConfig `json:"browser,omitempty"` // 覆盖 browser
+}
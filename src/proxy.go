@@ -0,0 +1,247 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ProxyConfig 本地认证代理：在回环地址上转发到上游，并自动附加认证信息
+// 适合需要固定请求头或凭据、但不希望把凭据写进网址的内部系统
+type ProxyConfig struct {
+	Upstream string            `json:"upstream"`          // 上游地址，如 https://app.example.com
+	Port     int               `json:"port,omitempty"`    // 本地端口（0 由系统分配；固定端口可保证 Cookie 等数据不丢失）
+	Path     string            `json:"path,omitempty"`    // 打开的初始路径，默认 /
+	Headers  map[string]string `json:"headers,omitempty"` // 附加到每个请求的请求头
+	Auth     *ProxyAuth        `json:"auth,omitempty"`    // 认证信息
+}
+
+// ProxyAuth 代理附加的认证信息，密码或 token 可以写在配置中，也可以从系统凭据存储读取
+type ProxyAuth struct {
+	Type     string     `json:"type"`               // basic 或 bearer
+	Username string     `json:"username,omitempty"` // basic 认证用户名
+	Password string     `json:"password,omitempty"` // basic 认证密码
+	Token    string     `json:"token,omitempty"`    // bearer token
+	Header   string     `json:"header,omitempty"`   // 认证请求头，默认 Authorization
+	Secret   *SecretRef `json:"secret,omitempty"`   // 从系统凭据存储读取密码或 token（优先于配置中的值）
+}
+
+// SecretRef 系统凭据存储中的一项（Windows 凭据管理器、macOS 钥匙串、Linux Secret Service）
+type SecretRef struct {
+	Service string `json:"service"`
+	Account string `json:"account,omitempty"`
+}
+
+var (
+	proxyMu      sync.Mutex
+	proxyApplied *ProxyConfig // 当前生效的代理配置
+)
+
+// applyProxy 按配置启动、更新或停止认证代理（启动和热重载时调用）
+// 与静态站点共用本地服务的根路径，同时配置时以静态站点为准
+func applyProxy(cfg *ProxyConfig) error {
+	proxyMu.Lock()
+	defer proxyMu.Unlock()
+
+	if cfg != nil && config.GetStatic() != nil {
+		// 根路径已由静态站点接管
+		proxyApplied = nil
+		log.Println(T("log.proxy_static_conflict"))
+		return nil
+	}
+	if reflect.DeepEqual(cfg, proxyApplied) {
+		return nil
+	}
+	if cfg == nil {
+		localServer.SetRoot(nil)
+		proxyApplied = nil
+		return nil
+	}
+
+	handler, err := newProxyHandler(*cfg)
+	if err != nil {
+		return err
+	}
+	if err := localServer.Start(cfg.Port); err != nil {
+		return err
+	}
+	localServer.SetRoot(handler)
+	applied := *cfg
+	proxyApplied = &applied
+	log.Printf(T("log.proxy_serving"), localServer.URL(cfg.Path), cfg.Upstream)
+	return nil
+}
+
+// proxyURL 返回代理的本地地址，未启用时返回空
+func proxyURL() string {
+	proxyMu.Lock()
+	defer proxyMu.Unlock()
+	if proxyApplied == nil {
+		return ""
+	}
+	return localServer.URL(proxyApplied.Path)
+}
+
+// newProxyHandler 创建反向代理（支持 WebSocket）
+func newProxyHandler(cfg ProxyConfig) (http.Handler, error) {
+	upstream, err := url.Parse(cfg.Upstream)
+	if err != nil || upstream.Host == "" || (upstream.Scheme != "http" && upstream.Scheme != "https") {
+		return nil, fmt.Errorf(T("err.proxy_upstream"), cfg.Upstream)
+	}
+	if upstream.User != nil {
+		// 凭据不允许出现在网址中，避免写入日志或浏览器历史
+		return nil, errors.New(T("err.proxy_url_credentials"))
+	}
+
+	headers := make(http.Header)
+	keys := make([]string, 0, len(cfg.Headers))
+	for k := range cfg.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		headers.Set(k, cfg.Headers[k])
+	}
+	if cfg.Auth != nil {
+		name, value, err := authHeader(*cfg.Auth)
+		if err != nil {
+			return nil, err
+		}
+		headers.Set(name, value)
+	}
+
+	upstreamOrigin := upstream.Scheme + "://" + upstream.Host
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(upstream)
+			r.SetXForwarded()
+			for k, v := range headers {
+				r.Out.Header[k] = v
+			}
+			// 上游可能校验来源（尤其是 WebSocket 握手），改写为上游地址（请求已通过 allowProxyRequest 检查）
+			if r.Out.Header.Get("Origin") != "" {
+				r.Out.Header.Set("Origin", upstreamOrigin)
+			}
+			if ref, err := url.Parse(r.Out.Header.Get("Referer")); err == nil && localServer.IsLocalOrigin(ref.Scheme+"://"+ref.Host) {
+				r.Out.Header.Set("Referer", upstreamOrigin+ref.RequestURI())
+			}
+		},
+		ModifyResponse: func(resp *http.Response) error {
+			scheme := resp.Request.Header.Get("X-Forwarded-Proto")
+			localOrigin := scheme + "://" + resp.Request.Header.Get("X-Forwarded-Host")
+			rewriteLocation(resp, upstream, localOrigin)
+			rewriteCookies(resp, scheme == "https")
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			log.Printf(T("log.proxy_error"), r.URL.Path, err)
+			http.Error(w, T("err.proxy_bad_gateway", err), http.StatusBadGateway)
+		},
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowProxyRequest(r) {
+			log.Printf(T("log.proxy_forbidden"), r.Host, r.URL.Path, r.Header.Get("Origin"))
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		proxy.ServeHTTP(w, r)
+	}), nil
+}
+
+// allowProxyRequest 代理会附加认证信息，只接受本服务自身地址上的请求：
+// Host 须为回环地址或证书中的名称（拒绝 DNS 重绑定），Origin 须为本服务，
+// 其他网站发起的跨站请求（除打开链接外）一律拒绝
+func allowProxyRequest(r *http.Request) bool {
+	if !localServer.IsLocalHost(r.Host) {
+		return false
+	}
+	if origin := r.Header.Get("Origin"); origin != "" && !localServer.IsLocalOrigin(origin) {
+		return false
+	}
+	if r.Header.Get("Sec-Fetch-Site") == "cross-site" && r.Header.Get("Sec-Fetch-Mode") != "navigate" {
+		return false
+	}
+	return true
+}
+
+// authHeader 生成认证请求头
+func authHeader(auth ProxyAuth) (string, string, error) {
+	secret := ""
+	if auth.Secret != nil {
+		s, err := readSecret(auth.Secret.Service, auth.Secret.Account)
+		if err != nil {
+			return "", "", err
+		}
+		secret = s
+	}
+	name := auth.Header
+	if name == "" {
+		name = "Authorization"
+	}
+
+	switch strings.ToLower(auth.Type) {
+	case "basic":
+		password := auth.Password
+		if secret != "" {
+			password = secret
+		}
+		token := base64.StdEncoding.EncodeToString([]byte(auth.Username + ":" + password))
+		return name, "Basic " + token, nil
+	case "bearer":
+		token := auth.Token
+		if secret != "" {
+			token = secret
+		}
+		if auth.Header != "" {
+			// 自定义请求头（如 X-Api-Key）直接使用 token
+			return name, token, nil
+		}
+		return name, "Bearer " + token, nil
+	}
+	return "", "", fmt.Errorf(T("err.proxy_auth_type"), auth.Type)
+}
+
+// rewriteLocation 将指向上游的重定向改写为本地地址
+func rewriteLocation(resp *http.Response, upstream *url.URL, localOrigin string) {
+	loc := resp.Header.Get("Location")
+	if loc == "" {
+		return
+	}
+	u, err := url.Parse(loc)
+	if err != nil || !strings.EqualFold(u.Host, upstream.Host) {
+		return
+	}
+	u.Scheme = ""
+	u.Host = ""
+	resp.Header.Set("Location", localOrigin+u.String())
+}
+
+// rewriteCookies 去掉 Cookie 的 Domain 属性（本地为 http 时同时去掉 Secure），使其在本地地址上生效
+func rewriteCookies(resp *http.Response, secure bool) {
+	cookies := resp.Cookies()
+	if len(cookies) == 0 {
+		return
+	}
+	resp.Header.Del("Set-Cookie")
+	for _, c := range cookies {
+		c.Domain = ""
+		if secure {
+			resp.Header.Add("Set-Cookie", c.String())
+			continue
+		}
+		c.Secure = false
+		if c.SameSite == http.SameSiteNoneMode {
+			// SameSite=None 要求 Secure
+			c.SameSite = http.SameSiteLaxMode
+		}
+		resp.Header.Add("Set-Cookie", c.String())
+	}
+}
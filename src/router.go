@@ -0,0 +1,179 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// RouterConfig 路由模式：程序注册为默认浏览器，按规则把链接分发到不同的浏览器或浏览器配置
+type RouterConfig struct {
+	Rules    []RouteRule    `json:"rules"`    // 规则，按顺序匹配第一个
+	Fallback *BrowserConfig `json:"fallback"` // 没有规则匹配时使用的浏览器（必须指定 path）
+}
+
+// RouteRule 路由规则，所有非空条件都满足时匹配
+type RouteRule struct {
+	Name    string         `json:"name,omitempty"`    // 规则名称（日志中显示）
+	Host    string         `json:"host,omitempty"`    // 主机名通配符，* 匹配任意字符（如 *.client-a.com）
+	Path    string         `json:"path,omitempty"`    // 路径正则表达式
+	Source  string         `json:"source,omitempty"`  // 打开链接的程序名称通配符（如 outlook、slack*），无法获取时视为不匹配
+	Browser *BrowserConfig `json:"browser,omitempty"` // 使用的浏览器，空则使用 fallback
+}
+
+// isWebURL 是否为 http / https 链接
+func isWebURL(raw string) bool {
+	lower := strings.ToLower(strings.TrimSpace(raw))
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// globMatch 不区分大小写的通配符匹配，* 匹配任意字符，? 匹配单个字符
+func globMatch(pattern, s string) bool {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, `.*`)
+	expr = strings.ReplaceAll(expr, `\?`, `.`)
+	re, err := regexp.Compile(`(?i)^` + expr + `$`)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(s)
+}
+
+// match 判断规则是否匹配，正则表达式无效时返回错误
+func (r RouteRule) match(u *url.URL, source string) (bool, error) {
+	if r.Host != "" && !globMatch(r.Host, u.Hostname()) {
+		return false, nil
+	}
+	if r.Path != "" {
+		re, err := regexp.Compile(r.Path)
+		if err != nil {
+			return false, err
+		}
+		if !re.MatchString(u.Path) {
+			return false, nil
+		}
+	}
+	if r.Source != "" && (source == "" || !globMatch(r.Source, source)) {
+		return false, nil
+	}
+	return true, nil
+}
+
+// routeBrowser 返回链接应使用的浏览器和匹配的规则名称
+func routeBrowser(cfg *RouterConfig, raw, source string) (*BrowserConfig, string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, "", err
+	}
+	browser, rule := cfg.Fallback, ""
+	for i, r := range cfg.Rules {
+		ok, err := r.match(u, source)
+		if err != nil {
+			log.Printf(T("log.router_rule_invalid"), ruleName(r, i), err)
+			continue
+		}
+		if ok {
+			rule = ruleName(r, i)
+			if r.Browser != nil {
+				browser = r.Browser
+			}
+			break
+		}
+	}
+	// 程序自身是默认浏览器，不能再交给系统打开，否则会循环调用
+	if browser == nil || browser.Path == "" {
+		return nil, rule, errors.New(T("err.router_browser"))
+	}
+	return browser, rule, nil
+}
+
+func ruleName(r RouteRule, i int) string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return fmt.Sprintf("#%d", i+1)
+}
+
+// routerInvoked 本进程是否收到过作为默认浏览器转交的链接（说明程序已注册为默认浏览器）
+var routerInvoked atomic.Bool
+
+// routeDefaultOpens 程序自身打开网页且未指定浏览器时是否按路由规则选择浏览器：
+// 已作为默认浏览器收到过链接（交给系统打开会转回程序自身），或配置了 fallback 时才路由，
+// 否则仍交给系统默认浏览器，避免未写完的 router 配置影响正常打开
+func routeDefaultOpens(raw string) bool {
+	cfg := config.GetRouter()
+	if cfg == nil || !isWebURL(raw) {
+		return false
+	}
+	return routerInvoked.Load() || (cfg.Fallback != nil && cfg.Fallback.Path != "")
+}
+
+// dispatchURL 分发作为默认浏览器收到的链接
+func dispatchURL(raw, source string) error {
+	routerInvoked.Store(true)
+	return routeURL(raw, source)
+}
+
+// routeURL 按路由规则打开链接，source 为打开链接的程序名称（未知时为空）
+func routeURL(raw, source string) error {
+	cfg := config.GetRouter()
+	if cfg == nil {
+		return errors.New(T("err.router_disabled"))
+	}
+	browser, rule, err := routeBrowser(cfg, raw, source)
+	if err != nil {
+		return err
+	}
+	if rule == "" {
+		rule = "fallback"
+	}
+	if source == "" {
+		source = "-"
+	}
+	log.Printf(T("log.router_route"), raw, source, rule, browser.Path)
+	return openURLWith(raw, browser)
+}
+
+// runRouterCommand 处理 router 子命令：register 注册为默认浏览器，unregister 注销（供卸载程序调用）
+func runRouterCommand(args []string) int {
+	if len(args) == 0 || (args[0] != "register" && args[0] != "unregister") {
+		fmt.Println(T("console.router_usage"))
+		return 2
+	}
+
+	var err error
+	config, err = LoadConfig(*staticConfig)
+	if err != nil {
+		fmt.Println(T("console.load_config_failed", err))
+		return 1
+	}
+
+	if args[0] == "unregister" {
+		if err := unregisterDefaultBrowser(); err != nil {
+			fmt.Println(T("console.router_failed", err))
+			return 1
+		}
+		fmt.Println(T("console.router_unregistered"))
+		return 0
+	}
+
+	cfg := config.GetRouter()
+	if cfg == nil {
+		fmt.Println(T("console.router_none"))
+		return 1
+	}
+	if cfg.Fallback == nil || cfg.Fallback.Path == "" {
+		fmt.Println(T("console.router_failed", errors.New(T("err.router_browser"))))
+		return 1
+	}
+	if err := registerDefaultBrowser(); err != nil {
+		fmt.Println(T("console.router_failed", err))
+		return 1
+	}
+	fmt.Println(T("console.router_registered"))
+	return 0
+}
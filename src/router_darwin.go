@@ -0,0 +1,57 @@
+//go:build darwin
+
+package main
+
+/*
+#include <stdlib.h>
+
+int weblauncherSetDefaultHandler(const char *scheme);
+*/
+import "C"
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// registerDefaultBrowser 在 Info.plist 中声明 http / https 并设为默认处理程序（系统会弹窗确认）
+func registerDefaultBrowser() error {
+	if err := addBundleSchemes("http", "https"); err != nil {
+		return err
+	}
+	for _, scheme := range []string{"http", "https"} {
+		cs := C.CString(scheme)
+		status := C.weblauncherSetDefaultHandler(cs)
+		C.free(unsafe.Pointer(cs))
+		if status != 0 {
+			return fmt.Errorf("LSSetDefaultHandlerForURLScheme(%s): %d", scheme, int(status))
+		}
+	}
+	return nil
+}
+
+// unregisterDefaultBrowser 移除 http / https 声明，系统会改用其他浏览器
+func unregisterDefaultBrowser() error {
+	return removeBundleSchemes("http", "https")
+}
+
+// launcherApp macOS 的链接通过 Apple Event 传入，来源由事件的发送进程确定
+func launcherApp() string {
+	return ""
+}
+
+// processName 返回进程的可执行文件名
+func processName(pid int) string {
+	if pid <= 0 {
+		return ""
+	}
+	out, err := exec.Command("ps", "-o", "comm=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(strings.TrimSpace(string(out)))
+}
@@ -0,0 +1,90 @@
+//go:build linux
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// routerDesktopID 路由模式使用的 .desktop 文件
+const routerDesktopID = "weblauncher-router.desktop"
+
+// registerDefaultBrowser 生成处理 http / https 的 .desktop 文件并设为默认浏览器
+func registerDefaultBrowser() error {
+	mimeTypes := []string{"x-scheme-handler/http", "x-scheme-handler/https"}
+	if err := writeHandlerDesktop(routerDesktopID, mimeTypes...); err != nil {
+		return err
+	}
+	if err := setMimeDefault(routerDesktopID, mimeTypes...); err != nil {
+		return err
+	}
+	// 部分桌面环境（如 GNOME、KDE）另外记录默认浏览器
+	if _, err := exec.LookPath("xdg-settings"); err == nil {
+		exec.Command("xdg-settings", "set", "default-web-browser", routerDesktopID).Run()
+	}
+	return nil
+}
+
+// unregisterDefaultBrowser 删除 .desktop 文件及默认浏览器设置（之后由桌面环境选择其他浏览器）
+func unregisterDefaultBrowser() error {
+	return removeHandlerDesktop(routerDesktopID)
+}
+
+// launcherSkip 转发链接的中间程序，查找来源程序时跳过
+var launcherSkip = map[string]bool{
+	"xdg-open": true, "gio": true, "gio-launch-desktop": true, "kioclient": true, "kioclient5": true,
+	"exo-open": true, "gvfs-open": true, "sh": true, "dash": true, "bash": true, "zsh": true, "env": true,
+}
+
+// launcherApp 沿父进程查找打开链接的程序名称
+func launcherApp() string {
+	pid := os.Getppid()
+	for i := 0; i < 8 && pid > 1; i++ {
+		name := procName(pid)
+		if name == "" {
+			return ""
+		}
+		if !launcherSkip[name] {
+			return name
+		}
+		pid = procParent(pid)
+	}
+	return ""
+}
+
+// procName 进程的可执行文件名（无权限读取 exe 时使用 comm）
+func procName(pid int) string {
+	if exe, err := os.Readlink(fmt.Sprintf("/proc/%d/exe", pid)); err == nil {
+		name := filepath.Base(strings.TrimSuffix(exe, " (deleted)"))
+		// 脚本由解释器执行，comm 才是脚本名
+		if !launcherSkip[name] && !strings.HasPrefix(name, "python") && !strings.HasPrefix(name, "perl") {
+			return name
+		}
+	}
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/comm", pid))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// procParent 读取 /proc/<pid>/stat 中的父进程号
+func procParent(pid int) int {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0
+	}
+	// 进程名可能包含空格和括号，从最后一个 ) 之后解析
+	s := string(data)
+	fields := strings.Fields(s[strings.LastIndex(s, ")")+1:])
+	if len(fields) < 2 {
+		return 0
+	}
+	ppid, _ := strconv.Atoi(fields[1])
+	return ppid
+}
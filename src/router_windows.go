@@ -0,0 +1,113 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+// 路由模式在注册表中使用的名称
+const (
+	routerProgID  = "WebLauncherURL"                                 // 处理 http / https 的 ProgID
+	routerClient  = `Software\Clients\StartMenuInternet\WebLauncher` // 浏览器注册信息
+	routerAppName = "WebLauncher"                                    // RegisteredApplications 中的名称
+)
+
+// registerDefaultBrowser 注册为可选的浏览器，并打开系统「默认应用」设置
+// Windows 8 起不允许程序直接修改默认浏览器，需要用户在设置中选择
+func registerDefaultBrowser() error {
+	exe, _ := os.Executable()
+	abs, _ := filepath.Abs(exe)
+	title := config.GetTitle()
+	command := fmt.Sprintf(`"%s" -url "%%1"`, abs)
+	icon := fmt.Sprintf(`"%s",0`, abs)
+
+	values := []struct {
+		path, name, value string
+	}{
+		{`Software\Classes\` + routerProgID, "", "URL:" + title},
+		{`Software\Classes\` + routerProgID, "URL Protocol", ""},
+		{`Software\Classes\` + routerProgID + `\DefaultIcon`, "", icon},
+		{`Software\Classes\` + routerProgID + `\shell\open\command`, "", command},
+		{routerClient, "", title},
+		{routerClient + `\DefaultIcon`, "", icon},
+		{routerClient + `\shell\open\command`, "", fmt.Sprintf(`"%s"`, abs)},
+		{routerClient + `\Capabilities`, "ApplicationName", title},
+		{routerClient + `\Capabilities`, "ApplicationDescription", title},
+		{routerClient + `\Capabilities`, "ApplicationIcon", icon},
+		{routerClient + `\Capabilities\StartMenu`, "StartMenuInternet", routerAppName},
+		{routerClient + `\Capabilities\URLAssociations`, "http", routerProgID},
+		{routerClient + `\Capabilities\URLAssociations`, "https", routerProgID},
+		{`Software\RegisteredApplications`, routerAppName, routerClient + `\Capabilities`},
+	}
+	for _, v := range values {
+		k, _, err := registry.CreateKey(registry.CURRENT_USER, v.path, registry.SET_VALUE)
+		if err != nil {
+			return err
+		}
+		err = k.SetStringValue(v.name, v.value)
+		k.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	cmd := exec.Command("cmd", "/c", "start", "", "ms-settings:defaultapps")
+	hideWindow(cmd)
+	return cmd.Start()
+}
+
+// unregisterDefaultBrowser 删除浏览器注册信息
+func unregisterDefaultBrowser() error {
+	for _, path := range []string{`Software\Classes\` + routerProgID, routerClient} {
+		if err := deleteKeyTree(registry.CURRENT_USER, path); err != nil && err != registry.ErrNotExist {
+			return err
+		}
+	}
+	k, err := registry.OpenKey(registry.CURRENT_USER, `Software\RegisteredApplications`, registry.SET_VALUE)
+	if err != nil {
+		return nil
+	}
+	defer k.Close()
+	if err := k.DeleteValue(routerAppName); err != nil && err != registry.ErrNotExist {
+		return err
+	}
+	return nil
+}
+
+// launcherApp 返回父进程（打开链接的程序）的名称，不含 .exe
+func launcherApp() string {
+	snap, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return ""
+	}
+	defer windows.CloseHandle(snap)
+
+	names := make(map[uint32]string)
+	parents := make(map[uint32]uint32)
+	var entry windows.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+	for err = windows.Process32First(snap, &entry); err == nil; err = windows.Process32Next(snap, &entry) {
+		names[entry.ProcessID] = windows.UTF16ToString(entry.ExeFile[:])
+		parents[entry.ProcessID] = entry.ParentProcessID
+	}
+
+	// 跳过转发链接的命令行进程
+	pid := parents[uint32(os.Getpid())]
+	for i := 0; i < 4; i++ {
+		name := strings.TrimSuffix(strings.ToLower(names[pid]), ".exe")
+		if name != "cmd" && name != "conhost" && name != "rundll32" {
+			return name
+		}
+		pid = parents[pid]
+	}
+	return ""
+}
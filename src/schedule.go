@@ -0,0 +1,296 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Schedule 定时任务：按 cron 表达式打开网页或执行托盘动作
+type Schedule struct {
+	Name    string         `json:"name,omitempty"`    // 名称（托盘菜单和日志中显示）
+	Cron    string         `json:"cron"`              // cron 表达式（分 时 日 月 周），如 "0 8 * * mon-fri"
+	URL     string         `json:"url,omitempty"`     // 打开的网页
+	Browser *BrowserConfig `json:"browser,omitempty"` // 指定浏览器（空则使用 browser 配置）
+	Action  *MenuEntry     `json:"action,omitempty"`  // 执行的动作（同自定义菜单项），与 url 都为空时打开默认站点
+	Missed  string         `json:"missed,omitempty"`  // 错过的运行（如休眠期间）：run（默认，恢复后补运行一次）或 skip
+	Grace   Duration       `json:"grace,omitempty"`   // 补运行的最长延迟，超过后不再补运行，默认 1h
+}
+
+// 定时任务最近一次的结果
+const (
+	scheduleOK     = "ok"
+	scheduleFailed = "failed"
+	scheduleMissed = "missed" // 错过且未补运行
+	schedulePaused = "paused" // 暂停期间跳过
+)
+
+const (
+	scheduleTick      = 15 * time.Second // 检查间隔（使用墙上时间，休眠恢复后能发现错过的运行）
+	scheduleTolerance = time.Minute      // 延迟在此范围内视为按时运行
+	scheduleMenuSlots = 5                // 托盘菜单中显示的即将运行项数
+)
+
+// ScheduleStatus 定时任务状态（IPC 状态中输出）
+type ScheduleStatus struct {
+	Name   string    `json:"name"`
+	Cron   string    `json:"cron"`
+	Next   time.Time `json:"next"` // 下次运行时间（表达式无效时为零值）
+	Last   time.Time `json:"last"` // 最近一次计划运行的时间
+	Result string    `json:"result,omitempty"`
+	Error  string    `json:"error,omitempty"`
+}
+
+type scheduleEntry struct {
+	cfg  Schedule
+	spec *cronSpec
+	ScheduleStatus
+}
+
+type scheduler struct {
+	mu       sync.Mutex
+	cfg      []Schedule
+	entries  []*scheduleEntry
+	stop     chan struct{}
+	done     chan struct{}
+	onChange func()
+}
+
+var (
+	schedulesMu    sync.Mutex
+	schedules      *scheduler
+	schedulesPause bool // 暂停状态在热重载后保持
+)
+
+func init() {
+	registerStatus("schedules", func() interface{} {
+		s := currentScheduler()
+		if s == nil {
+			return nil
+		}
+		return struct {
+			Paused bool             `json:"paused"`
+			Items  []ScheduleStatus `json:"items"`
+		}{schedulesPaused(), s.Status()}
+	})
+}
+
+func currentScheduler() *scheduler {
+	schedulesMu.Lock()
+	defer schedulesMu.Unlock()
+	return schedules
+}
+
+// schedulesPaused 定时任务是否已暂停
+func schedulesPaused() bool {
+	schedulesMu.Lock()
+	defer schedulesMu.Unlock()
+	return schedulesPause
+}
+
+// setSchedulesPaused 暂停或恢复定时任务
+func setSchedulesPaused(paused bool) {
+	schedulesMu.Lock()
+	schedulesPause = paused
+	schedulesMu.Unlock()
+	if paused {
+		log.Println(T("log.schedule_paused"))
+	} else {
+		log.Println(T("log.schedule_resumed"))
+	}
+	updateScheduleMenu()
+}
+
+func newScheduler(cfg []Schedule) *scheduler {
+	s := &scheduler{cfg: cfg}
+	now := time.Now()
+	for i, c := range cfg {
+		e := &scheduleEntry{cfg: c}
+		e.Name = c.Name
+		if e.Name == "" {
+			e.Name = c.URL
+		}
+		if e.Name == "" {
+			e.Name = fmt.Sprintf("#%d", i+1)
+		}
+		e.Cron = c.Cron
+		spec, err := parseCron(c.Cron)
+		if err == nil && c.Action != nil && (c.Action.Type == ActionSubmenu || c.Action.Type == ActionSeparator) {
+			err = errors.New(T("err.schedule_action"))
+		}
+		if err != nil {
+			e.Error = err.Error()
+			log.Printf(T("log.schedule_invalid"), e.Name, err)
+		} else {
+			e.spec = spec
+			e.Next = spec.Next(now)
+		}
+		s.entries = append(s.entries, e)
+	}
+	return s
+}
+
+func (s *scheduler) start() {
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.loop()
+}
+
+func (s *scheduler) close() {
+	close(s.stop)
+	<-s.done
+}
+
+func (s *scheduler) loop() {
+	defer close(s.done)
+	ticker := time.NewTicker(scheduleTick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.check(time.Now())
+		}
+	}
+}
+
+// check 运行已到时间的任务；延迟超过 scheduleTolerance 的视为错过（通常是休眠期间），按 missed 设置处理
+func (s *scheduler) check(now time.Time) {
+	paused := schedulesPaused()
+	var due []*scheduleEntry
+	changed := false
+
+	s.mu.Lock()
+	for _, e := range s.entries {
+		if e.spec == nil || e.Next.IsZero() || now.Before(e.Next) {
+			continue
+		}
+		changed = true
+		late := now.Sub(e.Next)
+		e.Last = e.Next
+		e.Next = e.spec.Next(now)
+		switch {
+		case paused:
+			e.Result, e.Error = schedulePaused, ""
+			log.Printf(T("log.schedule_skipped_paused"), e.Name)
+		case late <= scheduleTolerance:
+			due = append(due, e)
+		case e.cfg.Missed == "skip" || late > e.cfg.Grace.Or(time.Hour):
+			e.Result, e.Error = scheduleMissed, ""
+			log.Printf(T("log.schedule_missed"), e.Name, e.Last.Format("2006-01-02 15:04"))
+		default:
+			log.Printf(T("log.schedule_catch_up"), e.Name, e.Last.Format("2006-01-02 15:04"))
+			due = append(due, e)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, e := range due {
+		go s.run(e)
+	}
+	if changed && s.onChange != nil {
+		s.onChange()
+	}
+}
+
+// run 执行任务并记录结果
+func (s *scheduler) run(e *scheduleEntry) {
+	err := runSchedule(e.Name, e.cfg)
+	s.mu.Lock()
+	if err != nil {
+		e.Result, e.Error = scheduleFailed, err.Error()
+	} else {
+		e.Result, e.Error = scheduleOK, ""
+	}
+	s.mu.Unlock()
+}
+
+func runSchedule(name string, c Schedule) error {
+	log.Printf(T("log.schedule_run"), name)
+	var err error
+	switch {
+	case c.Action != nil:
+		err = runAction(*c.Action)
+	case c.URL != "":
+		browser := c.Browser
+		if browser == nil {
+			browser = config.GetBrowser()
+		}
+		if waitTarget(c.URL) {
+			err = openURLWith(c.URL, browser)
+		}
+	default:
+		openDefault()
+	}
+	if err != nil {
+		log.Printf(T("log.schedule_failed"), name, err)
+	}
+	return err
+}
+
+// Status 返回各任务的状态
+func (s *scheduler) Status() []ScheduleStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]ScheduleStatus, len(s.entries))
+	for i, e := range s.entries {
+		list[i] = e.ScheduleStatus
+	}
+	return list
+}
+
+// Upcoming 返回按时间排序的即将运行的任务，最多 n 项
+func (s *scheduler) Upcoming(n int) []ScheduleStatus {
+	var list []ScheduleStatus
+	for _, st := range s.Status() {
+		if !st.Next.IsZero() {
+			list = append(list, st)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Next.Before(list[j].Next) })
+	if len(list) > n {
+		list = list[:n]
+	}
+	return list
+}
+
+// applySchedules 按配置启动、更新或停止定时任务（热重载时调用）
+func applySchedules(cfg []Schedule) {
+	schedulesMu.Lock()
+	old := schedules
+	if old != nil && reflect.DeepEqual(old.cfg, cfg) {
+		schedulesMu.Unlock()
+		return
+	}
+	var s *scheduler
+	if len(cfg) > 0 {
+		s = newScheduler(cfg)
+		s.onChange = updateScheduleMenu
+	}
+	schedules = s
+	schedulesMu.Unlock()
+
+	if old != nil {
+		old.close()
+	}
+	if s != nil {
+		s.start()
+	}
+	updateScheduleMenu()
+}
+
+// stopSchedules 停止定时任务（退出时调用）
+func stopSchedules() {
+	schedulesMu.Lock()
+	s := schedules
+	schedules = nil
+	schedulesMu.Unlock()
+	if s != nil {
+		s.close()
+	}
+}
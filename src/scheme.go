@@ -0,0 +1,341 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// SchemeConfig 自定义协议：点击 name://... 链接时由程序按路由打开对应网页
+type SchemeConfig struct {
+	Name   string        `json:"name"`   // 协议名，如 weblauncher
+	Routes []SchemeRoute `json:"routes"` // 路由，按顺序匹配第一个
+}
+
+// SchemeRoute 协议链接到网页地址的映射
+// path 中的 {name} 匹配一段路径，末尾的 {name...} 匹配剩余全部路径；url 中用 {name} 引用
+type SchemeRoute struct {
+	Path    string         `json:"path"`              // 路径模板，如 reports/{id}（不含协议名）
+	URL     string         `json:"url"`               // 目标地址模板，以 / 开头时为当前目标地址下的路径
+	Browser *BrowserConfig `json:"browser,omitempty"` // 指定浏览器
+}
+
+var schemeNamePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*$`)
+
+// reservedSchemes 不允许注册的常用协议
+var reservedSchemes = map[string]bool{
+	"http": true, "https": true, "file": true, "ftp": true, "mailto": true, "data": true, "javascript": true,
+}
+
+var (
+	schemeMu         sync.Mutex
+	schemeRegistered string // 本次运行已注册的协议名
+)
+
+// validSchemeName 检查协议名是否合法
+func validSchemeName(name string) error {
+	if !schemeNamePattern.MatchString(name) || reservedSchemes[strings.ToLower(name)] {
+		return fmt.Errorf(T("err.scheme_name"), name)
+	}
+	return nil
+}
+
+// applyScheme 注册配置中的协议，协议名变化或删除配置时注销之前注册的协议
+func applyScheme(cfg *SchemeConfig) error {
+	schemeMu.Lock()
+	defer schemeMu.Unlock()
+
+	name := ""
+	if cfg != nil {
+		name = strings.ToLower(cfg.Name)
+	}
+	previous := loadRegisteredScheme()
+	if previous != "" && previous != name {
+		if err := unregisterScheme(previous); err != nil {
+			log.Printf(T("log.scheme_unregister_failed"), previous, err)
+		} else {
+			log.Printf(T("log.scheme_unregistered"), previous)
+		}
+		saveRegisteredScheme("")
+	}
+	if name == "" || name == schemeRegistered {
+		schemeRegistered = name
+		return nil
+	}
+	if err := validSchemeName(name); err != nil {
+		return err
+	}
+	if err := registerScheme(name); err != nil {
+		return fmt.Errorf(T("err.scheme_register"), name, err)
+	}
+	schemeRegistered = name
+	saveRegisteredScheme(name)
+	log.Printf(T("log.scheme_registered"), name)
+	return nil
+}
+
+// schemeFile 记录已注册的协议名，协议名变化或卸载时据此注销
+func schemeFile() string {
+	if DataDir == "" || config.Static {
+		return ""
+	}
+	return filepath.Join(DataDir, "scheme")
+}
+
+func loadRegisteredScheme() string {
+	path := schemeFile()
+	if path == "" {
+		return ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func saveRegisteredScheme(name string) {
+	path := schemeFile()
+	if path == "" {
+		return
+	}
+	if name == "" {
+		os.Remove(path)
+		return
+	}
+	if err := os.WriteFile(path, []byte(name+"\n"), 0644); err != nil {
+		log.Printf(T("log.scheme_save_failed"), err)
+	}
+}
+
+// resolveSchemeURL 按路由把协议链接转换为网页地址
+func resolveSchemeURL(cfg *SchemeConfig, raw string) (string, *BrowserConfig, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", nil, err
+	}
+	if cfg == nil || !strings.EqualFold(u.Scheme, cfg.Name) {
+		return "", nil, fmt.Errorf(T("err.scheme_unknown"), raw)
+	}
+
+	// name://reports/42 的 host 为 reports；name:reports/42 为 opaque
+	path := u.Opaque
+	if path == "" {
+		path = u.Host + u.EscapedPath()
+	}
+	segments := splitSchemePath(path)
+	for _, r := range cfg.Routes {
+		vars, ok := matchSchemeRoute(splitSchemePath(r.Path), segments)
+		if !ok {
+			continue
+		}
+		target, err := expandSchemeURL(r.URL, vars)
+		if err != nil {
+			return "", nil, err
+		}
+		if u.RawQuery != "" {
+			target = mergeQuery(target, u.Query())
+		}
+		return target, r.Browser, nil
+	}
+	return "", nil, fmt.Errorf(T("err.scheme_no_route"), raw)
+}
+
+func splitSchemePath(p string) []string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+// matchSchemeRoute 匹配路径模板，返回各变量的值（已解码；{name...} 的值为逐段解码后的数组）
+func matchSchemeRoute(pattern, segments []string) (map[string][]string, bool) {
+	vars := make(map[string][]string)
+	for i, p := range pattern {
+		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "...}") && i == len(pattern)-1 {
+			var rest []string
+			if i < len(segments) {
+				rest = segments[i:]
+			}
+			values := make([]string, 0, len(rest))
+			for _, s := range rest {
+				v, err := schemeSegment(s)
+				if err != nil {
+					return nil, false
+				}
+				values = append(values, v)
+			}
+			vars[strings.TrimSuffix(p[1:], "...}")] = values
+			return vars, true
+		}
+		if i >= len(segments) {
+			return nil, false
+		}
+		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+			v, err := schemeSegment(segments[i])
+			if err != nil {
+				return nil, false
+			}
+			vars[p[1:len(p)-1]] = []string{v}
+			continue
+		}
+		if !strings.EqualFold(p, segments[i]) {
+			return nil, false
+		}
+	}
+	return vars, len(pattern) == len(segments)
+}
+
+// schemeSegment 解码一段路径，拒绝 . 和 ..（避免跳出目标路径）
+func schemeSegment(s string) (string, error) {
+	v, err := url.PathUnescape(s)
+	if err != nil {
+		return "", err
+	}
+	if v == "." || v == ".." {
+		return "", errors.New(s)
+	}
+	return v, nil
+}
+
+var schemeVarPattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// expandSchemeURL 替换目标地址模板中的变量：? 之前按路径编码，之后按查询参数编码
+func expandSchemeURL(tmpl string, vars map[string][]string) (string, error) {
+	queryAt := strings.Index(tmpl, "?")
+	var b strings.Builder
+	last := 0
+	for _, loc := range schemeVarPattern.FindAllStringSubmatchIndex(tmpl, -1) {
+		name := tmpl[loc[2]:loc[3]]
+		values, ok := vars[name]
+		if !ok {
+			return "", fmt.Errorf(T("err.scheme_var"), name)
+		}
+		b.WriteString(tmpl[last:loc[0]])
+		inQuery := queryAt >= 0 && loc[0] > queryAt
+		for i, v := range values {
+			if i > 0 {
+				b.WriteString("/")
+			}
+			if inQuery {
+				b.WriteString(url.QueryEscape(v))
+			} else {
+				b.WriteString(url.PathEscape(v))
+			}
+		}
+		last = loc[1]
+	}
+	b.WriteString(tmpl[last:])
+	target := b.String()
+
+	// 以 / 开头时为当前目标地址下的路径
+	if strings.HasPrefix(target, "/") {
+		base, err := url.Parse(targetURL())
+		if err != nil {
+			return "", err
+		}
+		base.RawQuery, base.Fragment = "", ""
+		return strings.TrimSuffix(base.String(), "/") + target, nil
+	}
+	return target, nil
+}
+
+// mergeQuery 把协议链接中的查询参数追加到目标地址（目标地址已有的参数优先）
+func mergeQuery(target string, extra url.Values) string {
+	u, err := url.Parse(target)
+	if err != nil {
+		return target
+	}
+	q := u.Query()
+	for k, vs := range extra {
+		if _, ok := q[k]; !ok {
+			q[k] = vs
+		}
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// openSchemeURL 打开协议链接对应的网页（会等待地址可访问，调用方不应在托盘事件中直接调用）
+// http / https 链接（作为默认浏览器收到的链接）交给路由模式处理，source 为打开链接的程序名称
+func openSchemeURL(raw, source string) {
+	if isWebURL(raw) {
+		if err := dispatchURL(raw, source); err != nil {
+			log.Printf(T("log.scheme_open_failed"), raw, err)
+		}
+		return
+	}
+	target, browser, err := resolveSchemeURL(config.GetScheme(), raw)
+	if err != nil {
+		log.Printf(T("log.scheme_open_failed"), raw, err)
+		return
+	}
+	log.Printf(T("log.scheme_open"), raw, target)
+	if !waitTarget(target) {
+		return
+	}
+	if browser == nil {
+		browser = config.GetBrowser()
+	}
+	if err := openURLWith(target, browser); err != nil {
+		log.Printf(T("log.open_failed"), err)
+	}
+}
+
+// runSchemeCommand 处理 scheme 子命令：register 注册协议，unregister 注销（供卸载程序调用）
+func runSchemeCommand(args []string) int {
+	if len(args) == 0 || (args[0] != "register" && args[0] != "unregister") {
+		fmt.Println(T("console.scheme_usage"))
+		return 2
+	}
+
+	var err error
+	config, err = LoadConfig(*staticConfig)
+	if err != nil {
+		fmt.Println(T("console.load_config_failed", err))
+		return 1
+	}
+
+	if args[0] == "register" {
+		cfg := config.GetScheme()
+		if cfg == nil {
+			fmt.Println(T("console.scheme_none"))
+			return 1
+		}
+		if err := applyScheme(cfg); err != nil {
+			fmt.Println(T("console.scheme_failed", err))
+			return 1
+		}
+		fmt.Println(T("console.scheme_registered", strings.ToLower(cfg.Name)))
+		return 0
+	}
+
+	// 注销已记录的协议和当前配置的协议
+	names := []string{loadRegisteredScheme()}
+	if cfg := config.GetScheme(); cfg != nil {
+		names = append(names, strings.ToLower(cfg.Name))
+	}
+	code := 0
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if name == "" || seen[name] || validSchemeName(name) != nil {
+			continue
+		}
+		seen[name] = true
+		if err := unregisterScheme(name); err != nil {
+			fmt.Println(T("console.scheme_failed", err))
+			code = 1
+			continue
+		}
+		fmt.Println(T("console.scheme_unregistered", name))
+	}
+	saveRegisteredScheme("")
+	return code
+}
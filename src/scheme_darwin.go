@@ -0,0 +1,125 @@
+//go:build darwin
+
+package main
+
+/*
+#cgo LDFLAGS: -framework Cocoa
+void weblauncherListenURLEvents(void);
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// lsregister 刷新 Launch Services 中的应用注册信息
+const lsregister = "/System/Library/Frameworks/CoreServices.framework/Frameworks/LaunchServices.framework/Support/lsregister"
+
+// appBundle 返回可执行文件所在的 .app 目录（macOS 只能为应用包注册 URL 协议）
+func appBundle() (string, error) {
+	exe, _ := os.Executable()
+	abs, err := filepath.EvalSymlinks(exe)
+	if err != nil {
+		return "", err
+	}
+	// xxx.app/Contents/MacOS/xxx
+	bundle := filepath.Dir(filepath.Dir(filepath.Dir(abs)))
+	if !strings.HasSuffix(bundle, ".app") {
+		return "", errors.New(T("err.scheme_bundle"))
+	}
+	return bundle, nil
+}
+
+// registerScheme 在应用包的 Info.plist 中声明协议并刷新 Launch Services
+func registerScheme(name string) error {
+	return addBundleSchemes(name)
+}
+
+// unregisterScheme 移除 Info.plist 中的协议声明
+func unregisterScheme(name string) error {
+	return removeBundleSchemes(name)
+}
+
+// bundleSchemes 读取 Info.plist 中已声明的协议
+func bundleSchemes(plist string) []string {
+	out, err := exec.Command("plutil", "-extract", "CFBundleURLTypes.0.CFBundleURLSchemes", "json", "-o", "-", plist).Output()
+	if err != nil {
+		return nil
+	}
+	var schemes []string
+	json.Unmarshal(out, &schemes)
+	return schemes
+}
+
+// addBundleSchemes 把协议加入 Info.plist 的 CFBundleURLTypes（保留已有的协议）
+// 修改 Info.plist 会使代码签名失效，签名发布的应用应在打包时声明协议
+func addBundleSchemes(names ...string) error {
+	bundle, err := appBundle()
+	if err != nil {
+		return err
+	}
+	plist := filepath.Join(bundle, "Contents", "Info.plist")
+	schemes := bundleSchemes(plist)
+	for _, name := range names {
+		found := false
+		for _, s := range schemes {
+			found = found || s == name
+		}
+		if !found {
+			schemes = append(schemes, name)
+		}
+	}
+	return writeBundleSchemes(bundle, plist, schemes)
+}
+
+// removeBundleSchemes 从 Info.plist 中移除协议
+func removeBundleSchemes(names ...string) error {
+	bundle, err := appBundle()
+	if err != nil {
+		return err
+	}
+	plist := filepath.Join(bundle, "Contents", "Info.plist")
+	var kept []string
+	for _, s := range bundleSchemes(plist) {
+		remove := false
+		for _, name := range names {
+			remove = remove || s == name
+		}
+		if !remove {
+			kept = append(kept, s)
+		}
+	}
+	return writeBundleSchemes(bundle, plist, kept)
+}
+
+func writeBundleSchemes(bundle, plist string, schemes []string) error {
+	if len(schemes) == 0 {
+		exec.Command("plutil", "-remove", "CFBundleURLTypes", plist).Run()
+	} else {
+		types, _ := json.Marshal([]map[string]interface{}{{
+			"CFBundleURLName":    config.GetTitle(),
+			"CFBundleURLSchemes": schemes,
+		}})
+		if out, err := exec.Command("plutil", "-replace", "CFBundleURLTypes", "-json", string(types), plist).CombinedOutput(); err != nil {
+			return fmt.Errorf("plutil: %v %s", err, strings.TrimSpace(string(out)))
+		}
+	}
+	return exec.Command(lsregister, "-f", bundle).Run()
+}
+
+// listenURLEvents macOS 通过 Apple Event（kAEGetURL）而不是命令行参数传递协议链接，
+// 应用运行中和由链接启动时都会收到，需在托盘启动前注册
+func listenURLEvents() {
+	C.weblauncherListenURLEvents()
+}
+
+//export weblauncherHandleURL
+func weblauncherHandleURL(u *C.char, pid C.int) {
+	go openSchemeURL(C.GoString(u), processName(int(pid)))
+}
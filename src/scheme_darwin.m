@@ -0,0 +1,40 @@
+//go:build darwin
+
+#import <Cocoa/Cocoa.h>
+#include "_cgo_export.h"
+
+// 接收 Launch Services 发送的打开 URL 事件，转交给 Go
+@interface WLURLEventHandler : NSObject
+- (void)handleGetURLEvent:(NSAppleEventDescriptor *)event withReplyEvent:(NSAppleEventDescriptor *)reply;
+@end
+
+@implementation WLURLEventHandler
+- (void)handleGetURLEvent:(NSAppleEventDescriptor *)event withReplyEvent:(NSAppleEventDescriptor *)reply {
+	NSString *url = [[event paramDescriptorForKeyword:keyDirectObject] stringValue];
+	pid_t pid = [[event attributeDescriptorForKeyword:keySenderPIDAttr] int32Value];
+	if (url != nil) {
+		weblauncherHandleURL((char *)[url UTF8String], pid);
+	}
+}
+@end
+
+void weblauncherListenURLEvents(void) {
+	static WLURLEventHandler *handler;
+	if (handler == nil) {
+		handler = [[WLURLEventHandler alloc] init];
+	}
+	[[NSAppleEventManager sharedAppleEventManager] setEventHandler:handler
+	                                                   andSelector:@selector(handleGetURLEvent:withReplyEvent:)
+	                                                 forEventClass:kInternetEventClass
+	                                                    andEventID:kAEGetURL];
+}
+
+// weblauncherSetDefaultHandler 把当前应用设为协议的默认处理程序
+int weblauncherSetDefaultHandler(const char *scheme) {
+	NSString *bundleID = [[NSBundle mainBundle] bundleIdentifier];
+	if (bundleID == nil) {
+		return -1;
+	}
+	return LSSetDefaultHandlerForURLScheme((__bridge CFStringRef)[NSString stringWithUTF8String:scheme],
+	                                       (__bridge CFStringRef)bundleID);
+}
@@ -0,0 +1,129 @@
+//go:build linux
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// xdgDir 返回 XDG 目录环境变量的值，未设置时使用 ~/ 下的默认目录
+func xdgDir(env, fallback string) string {
+	if dir := os.Getenv(env); dir != "" {
+		return dir
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, fallback)
+}
+
+// desktopFilePath 返回 ~/.local/share/applications 下的 .desktop 文件路径
+func desktopFilePath(id string) string {
+	return filepath.Join(xdgDir("XDG_DATA_HOME", ".local/share"), "applications", id)
+}
+
+// registerScheme 生成带 x-scheme-handler 的 .desktop 文件，并通过 xdg-mime 设为默认处理程序
+func registerScheme(name string) error {
+	id := "weblauncher-" + name + ".desktop"
+	if err := writeHandlerDesktop(id, "x-scheme-handler/"+name); err != nil {
+		return err
+	}
+	return setMimeDefault(id, "x-scheme-handler/"+name)
+}
+
+// unregisterScheme 删除 .desktop 文件，并从 mimeapps.list 中移除默认处理程序
+func unregisterScheme(name string) error {
+	return removeHandlerDesktop("weblauncher-" + name + ".desktop")
+}
+
+// writeHandlerDesktop 生成处理指定 MIME 类型的 .desktop 文件，链接通过 -url 传入
+func writeHandlerDesktop(id string, mimeTypes ...string) error {
+	exe, _ := os.Executable()
+	abs, _ := filepath.Abs(exe)
+
+	path := desktopFilePath(id)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	content := fmt.Sprintf(`[Desktop Entry]
+Type=Application
+Name=%s
+Exec="%s" -url %%u
+Terminal=false
+NoDisplay=true
+MimeType=%s;
+`, config.GetTitle(), abs, strings.Join(mimeTypes, ";"))
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return err
+	}
+	updateDesktopDatabase(filepath.Dir(path))
+	return nil
+}
+
+// setMimeDefault 通过 xdg-mime 设为默认处理程序（没有 xdg-mime 时依靠 .desktop 中的 MimeType 声明）
+func setMimeDefault(id string, mimeTypes ...string) error {
+	if _, err := exec.LookPath("xdg-mime"); err != nil {
+		return nil
+	}
+	args := append([]string{"default", id}, mimeTypes...)
+	out, err := exec.Command("xdg-mime", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("xdg-mime: %v %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// removeHandlerDesktop 删除 .desktop 文件，并从 mimeapps.list 中移除对它的引用
+func removeHandlerDesktop(id string) error {
+	path := desktopFilePath(id)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	updateDesktopDatabase(filepath.Dir(path))
+
+	list := filepath.Join(xdgDir("XDG_CONFIG_HOME", ".config"), "mimeapps.list")
+	data, err := os.ReadFile(list)
+	if err != nil {
+		return nil
+	}
+	lines := strings.Split(string(data), "\n")
+	kept := lines[:0]
+	changed := false
+	for _, line := range lines {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.HasPrefix(strings.TrimSpace(line), "[") {
+			kept = append(kept, line)
+			continue
+		}
+		var apps []string
+		for _, app := range strings.Split(value, ";") {
+			if app != "" && app != id {
+				apps = append(apps, app)
+			}
+		}
+		if len(apps) == len(strings.FieldsFunc(value, func(r rune) bool { return r == ';' })) {
+			kept = append(kept, line)
+			continue
+		}
+		changed = true
+		if len(apps) > 0 {
+			kept = append(kept, key+"="+strings.Join(apps, ";")+";")
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return os.WriteFile(list, []byte(strings.Join(kept, "\n")), 0644)
+}
+
+// updateDesktopDatabase 刷新 MIME 类型缓存（未安装 desktop-file-utils 时忽略）
+func updateDesktopDatabase(dir string) {
+	if _, err := exec.LookPath("update-desktop-database"); err == nil {
+		exec.Command("update-desktop-database", dir).Run()
+	}
+}
+
+// listenURLEvents Linux 上协议链接通过命令行参数传入，无需额外处理
+func listenURLEvents() {}
@@ -0,0 +1,78 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// registerScheme 在 HKCU\Software\Classes 下注册 URL 协议
+func registerScheme(name string) error {
+	exe, _ := os.Executable()
+	abs, _ := filepath.Abs(exe)
+
+	root := `Software\Classes\` + name
+	k, _, err := registry.CreateKey(registry.CURRENT_USER, root, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer k.Close()
+	if err := k.SetStringValue("", "URL:"+config.GetTitle()); err != nil {
+		return err
+	}
+	if err := k.SetStringValue("URL Protocol", ""); err != nil {
+		return err
+	}
+
+	values := map[string]string{
+		`DefaultIcon`:        fmt.Sprintf(`"%s",0`, abs),
+		`shell\open\command`: fmt.Sprintf(`"%s" -url "%%1"`, abs),
+	}
+	for sub, val := range values {
+		sk, _, err := registry.CreateKey(registry.CURRENT_USER, root+`\`+sub, registry.SET_VALUE)
+		if err != nil {
+			return err
+		}
+		err = sk.SetStringValue("", val)
+		sk.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unregisterScheme 删除注册的 URL 协议
+func unregisterScheme(name string) error {
+	err := deleteKeyTree(registry.CURRENT_USER, `Software\Classes\`+name)
+	if err == registry.ErrNotExist {
+		return nil
+	}
+	return err
+}
+
+// deleteKeyTree 递归删除注册表项（registry.DeleteKey 只能删除没有子项的项）
+func deleteKeyTree(base registry.Key, path string) error {
+	k, err := registry.OpenKey(base, path, registry.ENUMERATE_SUB_KEYS)
+	if err != nil {
+		return err
+	}
+	subs, err := k.ReadSubKeyNames(-1)
+	k.Close()
+	if err != nil {
+		return err
+	}
+	for _, sub := range subs {
+		if err := deleteKeyTree(base, path+`\`+sub); err != nil {
+			return err
+		}
+	}
+	return registry.DeleteKey(base, path)
+}
+
+// listenURLEvents Windows 上协议链接通过命令行参数传入，无需额外处理
+func listenURLEvents() {}
@@ -0,0 +1,23 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// readSecret 从钥匙串读取通用密码
+// 保存：security add-generic-password -s <service> -a <account> -w
+func readSecret(service, account string) (string, error) {
+	args := []string{"find-generic-password", "-s", service, "-w"}
+	if account != "" {
+		args = append(args, "-a", account)
+	}
+	out, err := exec.Command("security", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf(T("err.secret_read"), service, err)
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
@@ -0,0 +1,23 @@
+//go:build linux
+
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// readSecret 通过 secret-tool（libsecret，GNOME Keyring / KWallet）读取凭据
+// 保存：secret-tool store --label=WebLauncher service <service> account <account>
+func readSecret(service, account string) (string, error) {
+	args := []string{"lookup", "service", service}
+	if account != "" {
+		args = append(args, "account", account)
+	}
+	out, err := exec.Command("secret-tool", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf(T("err.secret_read"), service, err)
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
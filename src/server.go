@@ -0,0 +1,219 @@
+package main
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 本地回环 HTTP 服务
+// 根路径交给应用处理器（如静态站点），/__weblauncher/ 下为程序内部页面
+
+const internalPrefix = "/__weblauncher/"
+
+type LocalServer struct {
+	mu       sync.Mutex
+	srv      *http.Server
+	addr     string       // 实际监听地址 host:port
+	root     http.Handler // 应用处理器
+	internal *http.ServeMux
+	certs    *localCerts // 非空时使用 HTTPS
+}
+
+var localServer = &LocalServer{internal: http.NewServeMux()}
+
+// Start 在 127.0.0.1:port 上启动服务（port 为 0 时由系统分配），已启动且端口一致时不做任何事
+func (s *LocalServer) Start(port int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.srv != nil {
+		if port == 0 || strings.HasSuffix(s.addr, fmt.Sprintf(":%d", port)) {
+			return nil
+		}
+		s.stopLocked()
+	}
+	return s.startLocked(port)
+}
+
+func (s *LocalServer) startLocked(port int) error {
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return fmt.Errorf(T("err.server_listen"), err)
+	}
+	s.addr = ln.Addr().String()
+	if s.certs != nil {
+		ln = tls.NewListener(ln, &tls.Config{
+			GetCertificate: s.certs.GetCertificate,
+			MinVersion:     tls.VersionTLS12,
+		})
+	}
+	s.srv = &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Printf(T("log.server_started"), s.originLocked())
+
+	srv := s.srv
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf(T("log.server_error"), err)
+		}
+	}()
+	return nil
+}
+
+// Stop 关闭服务
+func (s *LocalServer) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopLocked()
+}
+
+func (s *LocalServer) stopLocked() {
+	if s.srv != nil {
+		s.srv.Close()
+		s.srv = nil
+		s.addr = ""
+	}
+}
+
+// Running 服务是否已启动
+func (s *LocalServer) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.srv != nil
+}
+
+// SetTLS 切换 HTTPS 证书，certs 为 nil 时使用 HTTP；服务已启动时在原端口上重新监听
+func (s *LocalServer) SetTLS(certs *localCerts) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if certs == s.certs {
+		return nil
+	}
+	s.certs = certs
+	if s.srv == nil {
+		return nil
+	}
+	_, port, _ := net.SplitHostPort(s.addr)
+	p, _ := strconv.Atoi(port)
+	s.stopLocked()
+	return s.startLocked(p)
+}
+
+// URL 返回本地服务上指定路径的完整地址
+func (s *LocalServer) URL(path string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return s.originLocked() + path
+}
+
+// originLocked 返回 scheme://host:port（调用方负责加锁）
+// HTTPS 时使用证书中的第一个名称（默认 localhost），保证与证书匹配
+func (s *LocalServer) originLocked() string {
+	if s.certs == nil {
+		return "http://" + s.addr
+	}
+	_, port, _ := net.SplitHostPort(s.addr)
+	return "https://" + net.JoinHostPort(s.certs.Host(), port)
+}
+
+// IsLocalHost 判断请求的 Host 是否指向本服务：回环地址或证书中的名称，且端口与监听端口一致
+// 用于拒绝 DNS 重绑定（其他域名解析到 127.0.0.1）的请求
+func (s *LocalServer) IsLocalHost(host string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.localHostLocked(host)
+}
+
+// IsLocalOrigin 判断 Origin 或 Referer 是否为本服务自身的地址
+func (s *LocalServer) IsLocalOrigin(origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	scheme := "http"
+	if s.certs != nil {
+		scheme = "https"
+	}
+	return strings.EqualFold(u.Scheme, scheme) && s.localHostLocked(u.Host)
+}
+
+func (s *LocalServer) localHostLocked(host string) bool {
+	if s.srv == nil {
+		return false
+	}
+	_, listenPort, _ := net.SplitHostPort(s.addr)
+	name, port, err := net.SplitHostPort(host)
+	if err != nil {
+		// 默认端口时 Host 不带端口
+		name, port = strings.Trim(host, "[]"), "80"
+		if s.certs != nil {
+			port = "443"
+		}
+	}
+	if port != listenPort {
+		return false
+	}
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	switch name {
+	case "127.0.0.1", "localhost", "::1":
+		return true
+	}
+	if s.certs != nil {
+		for _, n := range s.certs.names {
+			n = strings.ToLower(n)
+			if n == name {
+				return true
+			}
+			// 通配符只匹配一级子域名
+			if strings.HasPrefix(n, "*.") {
+				if label := strings.TrimSuffix(name, n[1:]); label != name && label != "" && !strings.Contains(label, ".") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// SetRoot 设置根路径的应用处理器，nil 表示不提供应用内容
+func (s *LocalServer) SetRoot(h http.Handler) {
+	s.mu.Lock()
+	s.root = h
+	s.mu.Unlock()
+}
+
+// Handle 注册内部页面，pattern 相对于 /__weblauncher/
+func (s *LocalServer) Handle(pattern string, h http.Handler) {
+	s.internal.Handle(internalPrefix+strings.TrimPrefix(pattern, "/"), h)
+}
+
+func (s *LocalServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, internalPrefix) {
+		s.internal.ServeHTTP(w, r)
+		return
+	}
+	s.mu.Lock()
+	root := s.root
+	s.mu.Unlock()
+	if root == nil {
+		http.NotFound(w, r)
+		return
+	}
+	root.ServeHTTP(w, r)
+}
@@ -0,0 +1,479 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SidecarConfig 随程序启动的本地后端进程（如 Python / Node 服务、jar）
+type SidecarConfig struct {
+	Command     string            `json:"command"`               // 可执行文件（含路径分隔符时相对配置文件所在目录）
+	Args        []string          `json:"args,omitempty"`        // 参数
+	Env         map[string]string `json:"env,omitempty"`         // 追加的环境变量
+	Dir         string            `json:"dir,omitempty"`         // 工作目录（相对路径基于配置文件所在目录）
+	Ready       *ReadyProbe       `json:"ready,omitempty"`       // 就绪探测，通过后才打开网页
+	StopTimeout Duration          `json:"stopTimeout,omitempty"` // 退出时等待进程结束的时间，超时后强制结束，默认 5s
+
+	Restart         string   `json:"restart,omitempty"`         // 重启策略：never（默认）、on-failure、always
+	RestartDelay    Duration `json:"restartDelay,omitempty"`    // 首次重启前的等待时间，之后每次翻倍，默认 1s
+	MaxRestartDelay Duration `json:"maxRestartDelay,omitempty"` // 重启等待时间上限，默认 1m
+	MaxRestarts     int      `json:"maxRestarts,omitempty"`     // restartWindow 内最多自动重启次数，超过视为崩溃循环，默认 5
+	RestartWindow   Duration `json:"restartWindow,omitempty"`   // 崩溃循环检测窗口，默认 1m；运行超过该时长后重启等待时间复位
+}
+
+// 重启策略
+const (
+	restartNever     = "never"
+	restartOnFailure = "on-failure"
+	restartAlways    = "always"
+)
+
+// 后端进程状态
+const (
+	sidecarStopped   = "stopped"   // 未运行（未启动或被手动停止）
+	sidecarRunning   = "running"   // 运行中
+	sidecarBackoff   = "backoff"   // 已退出，等待自动重启
+	sidecarExited    = "exited"    // 已退出，按策略不再重启
+	sidecarCrashLoop = "crashloop" // 短时间内反复退出，已停止自动重启
+)
+
+// SidecarStatus 后端进程状态（托盘菜单和 IPC status 使用）
+type SidecarStatus struct {
+	State       string     `json:"state"`
+	PID         int        `json:"pid,omitempty"`
+	ExitCode    *int       `json:"exitCode,omitempty"` // 上次退出码，-1 表示被信号结束或无法启动
+	Error       string     `json:"error,omitempty"`    // 上次退出或启动失败的原因
+	Restarts    int        `json:"restarts"`           // 自动重启次数
+	NextRestart *time.Time `json:"nextRestart,omitempty"`
+}
+
+// Sidecar 后端进程及其监护状态
+type Sidecar struct {
+	mu      sync.Mutex
+	cfg     SidecarConfig
+	cmd     *exec.Cmd
+	done    chan struct{} // 当前进程退出后关闭
+	err     error         // 上次退出结果
+	started time.Time     // 当前进程启动时间
+
+	state    string
+	exitCode *int
+	restarts int           // 自动重启次数
+	recent   []time.Time   // restartWindow 内的自动重启时间
+	delay    time.Duration // 下次重启前的等待时间
+	timer    *time.Timer   // 等待重启的定时器
+	next     time.Time     // 下次重启时间
+	onChange func()        // 状态变化回调
+}
+
+var (
+	sidecarMu sync.Mutex
+	sidecar   *Sidecar
+)
+
+func newSidecar(cfg SidecarConfig) *Sidecar {
+	return &Sidecar{cfg: cfg, state: sidecarStopped}
+}
+
+// SetOnChange 设置状态变化回调（在状态变化后调用，不持有锁）
+func (s *Sidecar) SetOnChange(fn func()) {
+	s.mu.Lock()
+	s.onChange = fn
+	s.mu.Unlock()
+}
+
+func (s *Sidecar) changed() {
+	s.mu.Lock()
+	fn := s.onChange
+	s.mu.Unlock()
+	if fn != nil {
+		fn()
+	}
+}
+
+// SetConfig 更新配置，进程参数在下次启动时生效，重启策略立即生效
+func (s *Sidecar) SetConfig(cfg SidecarConfig) {
+	s.mu.Lock()
+	s.cfg = cfg
+	s.mu.Unlock()
+}
+
+// Start 手动启动进程（清空崩溃计数），输出写入日志
+func (s *Sidecar) Start() error {
+	s.mu.Lock()
+	s.cancelRestartLocked()
+	s.recent = nil
+	s.delay = 0
+	err := s.startLocked()
+	if err != nil {
+		s.state = sidecarExited
+		s.err = err
+		code := -1
+		s.exitCode = &code
+	}
+	s.mu.Unlock()
+	s.changed()
+	return err
+}
+
+// startLocked 启动进程（调用方负责加锁），已运行时不做任何事
+func (s *Sidecar) startLocked() error {
+	if s.cmd != nil {
+		return nil
+	}
+	if s.cfg.Command == "" {
+		return errors.New(T("err.command_empty"))
+	}
+
+	command := s.cfg.Command
+	if strings.ContainsAny(command, `/\`) {
+		command = config.ResolvePath(command)
+	}
+	cmd := exec.Command(command, s.cfg.Args...)
+	cmd.Dir = config.ResolvePath(s.cfg.Dir)
+	if len(s.cfg.Env) > 0 {
+		keys := make([]string, 0, len(s.cfg.Env))
+		for k := range s.cfg.Env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		cmd.Env = os.Environ()
+		for _, k := range keys {
+			cmd.Env = append(cmd.Env, k+"="+s.cfg.Env[k])
+		}
+	}
+	setProcessGroup(cmd)
+
+	out := newLogWriter("sidecar")
+	cmd.Stdout = out
+	cmd.Stderr = out
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf(T("err.sidecar_start"), err)
+	}
+	log.Printf(T("log.sidecar_started"), cmd.Process.Pid, strings.Join(cmd.Args, " "))
+
+	s.cmd = cmd
+	s.err = nil
+	s.started = time.Now()
+	s.state = sidecarRunning
+	done := make(chan struct{})
+	s.done = done
+	go func() {
+		err := cmd.Wait()
+		out.Flush()
+		if err != nil {
+			log.Printf(T("log.sidecar_exited_error"), err)
+		} else {
+			log.Println(T("log.sidecar_exited"))
+		}
+		s.handleExit(cmd, err)
+		close(done)
+	}()
+	return nil
+}
+
+// handleExit 记录退出结果，并按重启策略决定是否重启
+func (s *Sidecar) handleExit(cmd *exec.Cmd, err error) {
+	s.mu.Lock()
+	if s.cmd != cmd {
+		s.mu.Unlock()
+		return
+	}
+	s.cmd = nil
+	s.err = err
+	code := exitCode(err)
+	s.exitCode = &code
+	if s.state == sidecarRunning {
+		s.afterExitLocked(err, time.Since(s.started))
+	}
+	s.mu.Unlock()
+	s.changed()
+}
+
+// afterExitLocked 按重启策略安排重启（调用方负责加锁）
+func (s *Sidecar) afterExitLocked(err error, ranFor time.Duration) {
+	restart := false
+	switch s.cfg.Restart {
+	case restartAlways:
+		restart = true
+	case restartOnFailure:
+		restart = err != nil
+	}
+	if !restart {
+		s.state = sidecarExited
+		return
+	}
+
+	window := s.cfg.RestartWindow.Or(time.Minute)
+	now := time.Now()
+	// 稳定运行一段时间后退出，重新从最短等待时间开始
+	if ranFor >= window {
+		s.delay = 0
+	}
+	recent := s.recent[:0]
+	for _, t := range s.recent {
+		if now.Sub(t) < window {
+			recent = append(recent, t)
+		}
+	}
+	s.recent = recent
+	maxRestarts := s.cfg.MaxRestarts
+	if maxRestarts <= 0 {
+		maxRestarts = 5
+	}
+	if len(s.recent) >= maxRestarts {
+		s.state = sidecarCrashLoop
+		log.Printf(T("log.sidecar_crashloop"), len(s.recent), window)
+		return
+	}
+
+	if s.delay == 0 {
+		s.delay = s.cfg.RestartDelay.Or(time.Second)
+	} else {
+		s.delay *= 2
+	}
+	if max := s.cfg.MaxRestartDelay.Or(time.Minute); s.delay > max {
+		s.delay = max
+	}
+	s.state = sidecarBackoff
+	s.next = now.Add(s.delay)
+	log.Printf(T("log.sidecar_restart_in"), s.delay)
+	s.timer = time.AfterFunc(s.delay, s.autoRestart)
+}
+
+// autoRestart 等待结束后自动重启
+func (s *Sidecar) autoRestart() {
+	s.mu.Lock()
+	if s.state != sidecarBackoff {
+		s.mu.Unlock()
+		return
+	}
+	s.timer = nil
+	s.recent = append(s.recent, time.Now())
+	s.restarts++
+	if err := s.startLocked(); err != nil {
+		log.Print(err)
+		s.err = err
+		code := -1
+		s.exitCode = &code
+		s.afterExitLocked(err, 0)
+	}
+	s.mu.Unlock()
+	s.changed()
+}
+
+func (s *Sidecar) cancelRestartLocked() {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+}
+
+// exitCode 返回进程退出码，被信号结束或无法获取时为 -1
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// WaitReady 等待就绪探测通过，未配置探测时立即返回；进程提前退出时返回错误
+func (s *Sidecar) WaitReady(ctx context.Context) error {
+	s.mu.Lock()
+	probe, done := s.cfg.Ready, s.done
+	s.mu.Unlock()
+	if probe == nil || done == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	err := waitUntil(ctx, probe.Timeout.Or(30*time.Second), probe.Interval.Or(500*time.Millisecond), probe.Check)
+	select {
+	case <-done:
+		return fmt.Errorf(T("err.sidecar_exited"), s.exitErr())
+	default:
+	}
+	if err != nil {
+		return fmt.Errorf(T("err.sidecar_not_ready"), err)
+	}
+	log.Println(T("log.sidecar_ready"))
+	return nil
+}
+
+func (s *Sidecar) exitErr() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Stop 请求进程退出，超时后强制结束；手动停止后不再自动重启
+func (s *Sidecar) Stop() {
+	s.mu.Lock()
+	s.cancelRestartLocked()
+	cmd, done := s.cmd, s.done
+	if s.state != sidecarExited || cmd != nil {
+		s.state = sidecarStopped
+	}
+	timeout := s.cfg.StopTimeout.Or(5 * time.Second)
+	s.mu.Unlock()
+	if cmd == nil {
+		s.changed()
+		return
+	}
+
+	select {
+	case <-done:
+	default:
+		log.Println(T("log.sidecar_stopping"))
+		if err := terminateProcess(cmd.Process); err != nil {
+			log.Printf(T("log.sidecar_terminate_failed"), err)
+		}
+		select {
+		case <-done:
+		case <-time.After(timeout):
+			log.Println(T("log.sidecar_killing"))
+			killProcess(cmd.Process)
+			select {
+			case <-done:
+			case <-time.After(2 * time.Second):
+			}
+		}
+	}
+
+	s.mu.Lock()
+	if s.cmd == cmd {
+		s.cmd = nil
+	}
+	s.mu.Unlock()
+	s.changed()
+}
+
+// Restart 停止后重新启动
+func (s *Sidecar) Restart() error {
+	s.Stop()
+	return s.Start()
+}
+
+// Status 返回当前状态
+func (s *Sidecar) Status() SidecarStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := SidecarStatus{State: s.state, ExitCode: s.exitCode, Restarts: s.restarts}
+	if s.cmd != nil {
+		st.PID = s.cmd.Process.Pid
+	}
+	if s.err != nil {
+		st.Error = s.err.Error()
+	}
+	if s.state == sidecarBackoff {
+		next := s.next
+		st.NextRestart = &next
+	}
+	return st
+}
+
+// currentSidecar 返回当前的后端进程（未配置时为 nil）
+func currentSidecar() *Sidecar {
+	sidecarMu.Lock()
+	defer sidecarMu.Unlock()
+	return sidecar
+}
+
+// startSidecar 按配置启动后端进程（未配置时不做任何事）
+func startSidecar() {
+	cfg := config.GetSidecar()
+	if cfg == nil {
+		return
+	}
+	s := newSidecar(*cfg)
+	s.SetOnChange(updateSidecarMenu)
+	sidecarMu.Lock()
+	sidecar = s
+	sidecarMu.Unlock()
+	if err := s.Start(); err != nil {
+		reportConfigError("%v", err)
+	}
+}
+
+// applySidecar 热重载时按新配置启动、停止或更新后端进程
+// 命令、参数、环境变量或工作目录变化时重启进程，其他字段直接生效
+func applySidecar(cfg *SidecarConfig) {
+	s := currentSidecar()
+	switch {
+	case cfg == nil && s == nil:
+		return
+	case cfg == nil:
+		sidecarMu.Lock()
+		sidecar = nil
+		sidecarMu.Unlock()
+		s.Stop()
+		return
+	case s == nil:
+		startSidecar()
+		return
+	}
+
+	s.mu.Lock()
+	old := s.cfg
+	s.mu.Unlock()
+	if reflect.DeepEqual(old, *cfg) {
+		return
+	}
+	s.SetConfig(*cfg)
+	if old.Command != cfg.Command || old.Dir != cfg.Dir ||
+		!reflect.DeepEqual(old.Args, cfg.Args) || !reflect.DeepEqual(old.Env, cfg.Env) {
+		log.Println(T("log.sidecar_config_changed"))
+		if err := s.Restart(); err != nil {
+			log.Print(err)
+		}
+	}
+}
+
+// waitSidecar 等待后端进程就绪（未配置时立即返回）
+func waitSidecar() {
+	s := currentSidecar()
+	if s == nil {
+		return
+	}
+	if err := s.WaitReady(context.Background()); err != nil {
+		log.Print(err)
+	}
+}
+
+// stopSidecar 结束后端进程
+func stopSidecar() {
+	if s := currentSidecar(); s != nil {
+		s.Stop()
+	}
+}
+
+func init() {
+	registerStatus("sidecar", func() interface{} {
+		if s := currentSidecar(); s != nil {
+			return s.Status()
+		}
+		return nil
+	})
+}
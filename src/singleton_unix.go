@@ -3,6 +3,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -23,13 +24,13 @@ func NewSingleton(name string) (*Singleton, error) {
 	// 尝试创建并锁定文件
 	file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0666)
 	if err != nil {
-		return nil, fmt.Errorf("无法创建锁文件: %w", err)
+		return nil, fmt.Errorf(T("err.lock_file"), err)
 	}
 
 	// 尝试获取文件锁（非阻塞）
 	if err := flock(file); err != nil {
 		file.Close()
-		return nil, fmt.Errorf("程序已在运行")
+		return nil, errors.New(T("err.already_running"))
 	}
 
 	// 写入 PID 便于调试
@@ -3,6 +3,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"unsafe"
 
@@ -33,15 +34,15 @@ func NewSingleton(name string) (*Singleton, error) {
 	if err != nil {
 		// 检查是否是已存在的错误
 		if err == windows.ERROR_ALREADY_EXISTS {
-			return nil, fmt.Errorf("程序已在运行")
+			return nil, errors.New(T("err.already_running"))
 		}
-		return nil, fmt.Errorf("创建互斥量失败: %w", err)
+		return nil, fmt.Errorf(T("err.mutex"), err)
 	}
 
 	// 再次检查 GetLastError（CreateMutex 成功时可能返回 ERROR_ALREADY_EXISTS）
 	if windows.GetLastError() == windows.ERROR_ALREADY_EXISTS {
 		windows.CloseHandle(handle)
-		return nil, fmt.Errorf("程序已在运行")
+		return nil, errors.New(T("err.already_running"))
 	}
 
 	return &Singleton{
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+)
+
+// Site 托盘子菜单中的一个站点
+type Site struct {
+	Title   string         `json:"title"`
+	URL     string         `json:"url"`
+	Icon    string         `json:"icon,omitempty"`    // 菜单项图标路径（可选）
+	Browser *BrowserConfig `json:"browser,omitempty"` // 指定浏览器（空则使用系统默认浏览器）
+	Default bool           `json:"default,omitempty"` // 双击托盘图标时打开的站点
+	Form    *FormConfig    `json:"form,omitempty"`    // 通过自动提交的表单打开（url 可为空）
+}
+
+// Target 站点的访问地址（表单站点未填写 url 时为表单提交地址），用于就绪等待和菜单提示
+func (s Site) Target() string {
+	if s.URL == "" && s.Form != nil {
+		return s.Form.Action
+	}
+	return s.URL
+}
+
+// BrowserConfig 指定打开网页所用的浏览器
+type BrowserConfig struct {
+	Path string   `json:"path"`           // 浏览器可执行文件路径
+	Args []string `json:"args,omitempty"` // 额外参数，URL 追加在最后
+}
+
+// openURLWith 使用指定浏览器打开 URL，未指定时使用系统默认浏览器
+func openURLWith(url string, b *BrowserConfig) error {
+	if b == nil || b.Path == "" {
+		// 路由模式下程序本身可能是默认浏览器，直接按规则选择浏览器，避免经系统转一圈
+		if routeDefaultOpens(url) {
+			return routeURL(url, "")
+		}
+		return openBrowser(url)
+	}
+	cmd, err := startBrowser(url, b)
+	if err != nil {
+		return err
+	}
+	// 回收子进程，避免僵尸进程
+	go cmd.Wait()
+	return nil
+}
+
+// startBrowser 用指定浏览器打开网页并返回浏览器进程，调用方负责 Wait
+func startBrowser(url string, b *BrowserConfig) (*exec.Cmd, error) {
+	args := append(append([]string{}, b.Args...), url)
+	cmd := exec.Command(b.Path, args...)
+	setWindowProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf(T("err.browser_start"), b.Path, err)
+	}
+	return cmd, nil
+}
+
+// openSite 打开站点
+func openSite(s Site) {
+	var err error
+	if s.Form != nil {
+		err = openForm(s.Title, s.Form, s.Browser)
+	} else {
+		err = openURLWith(s.URL, s.Browser)
+	}
+	if err != nil {
+		log.Printf(T("log.site_open_failed"), s.Title, err)
+	}
+}
+
+// openDefault 打开默认站点，未配置默认站点时打开目标地址
+// 配置了 wakeOnLan 时先发送唤醒魔术包，配置了 wait 时会先等待地址可访问，调用方不应在托盘事件中直接调用
+func openDefault() {
+	wakeHost()
+	if s, ok := config.GetDefaultSite(); ok {
+		if waitTarget(s.Target()) {
+			openSite(s)
+		}
+		return
+	}
+	ensureNetwork()
+	refreshMirror()
+	target := targetURL()
+	if !waitTarget(target) {
+		return
+	}
+	if err := openURLWith(target, config.GetBrowser()); err != nil {
+		log.Printf(T("log.open_failed"), err)
+	}
+}
+
+// siteIconData 读取站点图标，读取失败时返回 nil
+func siteIconData(s Site) []byte {
+	if s.Icon == "" {
+		return nil
+	}
+	data, err := loadTrayIcon(config.ResolvePath(s.Icon))
+	if err != nil {
+		log.Printf(T("log.site_icon_failed"), s.Icon, err)
+		return nil
+	}
+	return data
+}
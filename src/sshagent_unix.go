@@ -0,0 +1,19 @@
+//go:build !windows
+
+package main
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+)
+
+// dialSSHAgent 连接 ssh-agent（SSH_AUTH_SOCK）
+func dialSSHAgent() (io.ReadWriteCloser, error) {
+	sock := os.Getenv("SSH_AUTH_SOCK")
+	if sock == "" {
+		return nil, errors.New(T("err.ssh_agent_missing"))
+	}
+	return net.Dial("unix", sock)
+}
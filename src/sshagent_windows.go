@@ -0,0 +1,21 @@
+//go:build windows
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Windows 自带 OpenSSH 的 ssh-agent 服务使用命名管道
+const sshAgentPipe = `\\.\pipe\openssh-ssh-agent`
+
+// dialSSHAgent 连接 OpenSSH ssh-agent 服务
+func dialSSHAgent() (io.ReadWriteCloser, error) {
+	f, err := os.OpenFile(sshAgentPipe, os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf(T("err.ssh_agent_connect"), err)
+	}
+	return f, nil
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// StartupURL 托盘启动时依次打开的网页
+type StartupURL struct {
+	URL      string         `json:"url,omitempty"`      // 网页地址，空则打开默认站点或当前目标地址
+	Browser  *BrowserConfig `json:"browser,omitempty"`  // 指定浏览器（空则使用 browser 配置）
+	Delay    Duration       `json:"delay,omitempty"`    // 打开前等待的时间（从上一项打开后计算）
+	Days     []string       `json:"days,omitempty"`     // 只在这些日子打开：mon、tue … sun，空则不限
+	Time     string         `json:"time,omitempty"`     // 只在该时间段内打开，如 08:00-10:30（可跨午夜），空则不限
+	Profiles []string       `json:"profiles,omitempty"` // 只在这些环境下打开，空则不限
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// parseWeekday 解析星期（mon 或 monday，不区分大小写）
+func parseWeekday(s string) (time.Weekday, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if len(name) > 3 {
+		name = name[:3]
+	}
+	d, ok := weekdayNames[name]
+	if !ok {
+		return 0, fmt.Errorf(T("err.weekday"), s)
+	}
+	return d, nil
+}
+
+// parseClock 解析 HH:MM，返回当天的分钟数
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf(T("err.clock"), s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// inTimeWindow 判断 now 是否在 HH:MM-HH:MM 时间段内，结束时间早于开始时间时表示跨午夜
+func inTimeWindow(window string, now time.Time) (bool, error) {
+	from, to, ok := strings.Cut(window, "-")
+	if !ok {
+		return false, fmt.Errorf(T("err.time_window"), window)
+	}
+	start, err := parseClock(from)
+	if err != nil {
+		return false, err
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return false, err
+	}
+	minute := now.Hour()*60 + now.Minute()
+	if start <= end {
+		return minute >= start && minute < end, nil
+	}
+	return minute >= start || minute < end, nil
+}
+
+// active 判断当前是否满足打开条件
+func (s StartupURL) active(now time.Time, profile string) (bool, error) {
+	if len(s.Days) > 0 {
+		match := false
+		for _, name := range s.Days {
+			d, err := parseWeekday(name)
+			if err != nil {
+				return false, err
+			}
+			match = match || d == now.Weekday()
+		}
+		if !match {
+			return false, nil
+		}
+	}
+	if s.Time != "" {
+		ok, err := inTimeWindow(s.Time, now)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	if len(s.Profiles) > 0 {
+		match := false
+		for _, p := range s.Profiles {
+			match = match || p == profile
+		}
+		if !match {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// openStartupURLs 依次打开启动网页，未配置时返回 false
+func openStartupURLs() bool {
+	entries := config.GetStartupURLs()
+	if len(entries) == 0 {
+		return false
+	}
+	for i, s := range entries {
+		if s.Delay > 0 {
+			time.Sleep(time.Duration(s.Delay))
+		}
+		name := s.URL
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		ok, err := s.active(time.Now(), config.GetActiveProfile())
+		if err != nil {
+			log.Printf(T("log.startup_invalid"), name, err)
+			continue
+		}
+		if !ok {
+			log.Printf(T("log.startup_skipped"), name)
+			continue
+		}
+		if s.URL == "" {
+			openDefault()
+			continue
+		}
+		if !waitTarget(s.URL) {
+			continue
+		}
+		browser := s.Browser
+		if browser == nil {
+			browser = config.GetBrowser()
+		}
+		log.Printf(T("log.startup_open"), s.URL)
+		if err := openURLWith(s.URL, browser); err != nil {
+			log.Printf(T("log.open_failed"), err)
+		}
+	}
+	return true
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// StaticConfig 静态站点模式：由本地回环服务提供网页，不依赖远程服务器
+type StaticConfig struct {
+	Dir   string `json:"dir,omitempty"`   // 网页目录（相对路径基于配置文件所在目录，空则使用构建时嵌入的资源）
+	Port  int    `json:"port,omitempty"`  // 监听端口（0 由系统分配；固定端口可保证 localStorage 等数据不丢失）
+	Index string `json:"index,omitempty"` // 首页文件，默认 index.html
+	SPA   bool   `json:"spa,omitempty"`   // 启用 History 路由回退：找不到的页面路径返回首页
+	Path  string `json:"path,omitempty"`  // 打开的初始路径，默认 /
+}
+
+// 构建时嵌入的网页资源（未嵌入时为 nil），版本为归档的内容哈希
+var (
+	embeddedWeb        fs.FS
+	embeddedWebVersion string
+)
+
+// 部分系统（尤其是 Windows 注册表被修改过时）的 MIME 类型不可靠，内置常用类型
+var webMimeTypes = map[string]string{
+	".html":        "text/html; charset=utf-8",
+	".htm":         "text/html; charset=utf-8",
+	".css":         "text/css; charset=utf-8",
+	".js":          "text/javascript; charset=utf-8",
+	".mjs":         "text/javascript; charset=utf-8",
+	".json":        "application/json",
+	".map":         "application/json",
+	".webmanifest": "application/manifest+json",
+	".wasm":        "application/wasm",
+	".svg":         "image/svg+xml",
+	".png":         "image/png",
+	".jpg":         "image/jpeg",
+	".jpeg":        "image/jpeg",
+	".gif":         "image/gif",
+	".webp":        "image/webp",
+	".avif":        "image/avif",
+	".ico":         "image/x-icon",
+	".woff":        "font/woff",
+	".woff2":       "font/woff2",
+	".ttf":         "font/ttf",
+	".otf":         "font/otf",
+	".txt":         "text/plain; charset=utf-8",
+	".xml":         "application/xml",
+	".pdf":         "application/pdf",
+	".mp4":         "video/mp4",
+	".webm":        "video/webm",
+	".mp3":         "audio/mpeg",
+}
+
+func init() {
+	for ext, typ := range webMimeTypes {
+		mime.AddExtensionType(ext, typ)
+	}
+}
+
+// hashedAsset 匹配带内容哈希的文件名（如 app.3f2a9c1b.js、index-BdX3k9aQ.css），可长期缓存
+var hashedAsset = regexp.MustCompile(`[.-][0-9A-Za-z_]{8,}\.[0-9a-z]+$`)
+
+// staticHandler 提供静态文件，支持预压缩文件（.br / .gz）和 History 路由回退
+type staticHandler struct {
+	fsys    fs.FS
+	index   string
+	spa     bool
+	version string // 资源版本（内容哈希），非空时用于生成 ETag
+}
+
+func newStaticHandler(fsys fs.FS, cfg StaticConfig, version string) *staticHandler {
+	index := cfg.Index
+	if index == "" {
+		index = "index.html"
+	}
+	return &staticHandler{fsys: fsys, index: index, spa: cfg.SPA, version: version}
+}
+
+func (h *staticHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	if name == "" {
+		name = h.index
+	}
+	info, err := fs.Stat(h.fsys, name)
+	if err == nil && info.IsDir() {
+		name = path.Join(name, h.index)
+		_, err = fs.Stat(h.fsys, name)
+	}
+	if err != nil {
+		// 只对页面路由回退，缺失的静态资源（常见网页资源扩展名）仍返回 404，避免把 HTML 当作脚本加载；
+		// /users/john.doe 这类带点的页面路由仍然回退
+		if !h.spa || webMimeTypes[strings.ToLower(path.Ext(name))] != "" {
+			http.NotFound(w, r)
+			return
+		}
+		name = h.index
+	}
+	h.serveFile(w, r, name)
+}
+
+// serveFile 输出文件，客户端支持时优先使用预压缩版本
+func (h *staticHandler) serveFile(w http.ResponseWriter, r *http.Request, name string) {
+	header := w.Header()
+	ext := strings.ToLower(path.Ext(name))
+	if typ := mime.TypeByExtension(ext); typ != "" {
+		header.Set("Content-Type", typ)
+	}
+	switch {
+	case ext == ".html" || ext == ".htm":
+		header.Set("Cache-Control", "no-cache") // 页面每次校验，保证更新后立即生效
+	case hashedAsset.MatchString(name):
+		header.Set("Cache-Control", "public, max-age=31536000, immutable")
+	default:
+		header.Set("Cache-Control", "public, max-age=3600")
+	}
+	header.Add("Vary", "Accept-Encoding")
+
+	file, encoding := h.openEncoded(name, r.Header.Get("Accept-Encoding"))
+	if file == nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+	if encoding != "" {
+		header.Set("Content-Encoding", encoding)
+	}
+	if h.version != "" {
+		// 嵌入资源的修改时间不可靠，使用内容哈希作为 ETag 以支持 304
+		header.Set("ETag", fmt.Sprintf(`"%s-%x"`, h.version, name+"|"+encoding))
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	content, ok := file.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(file)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		content = bytes.NewReader(data)
+	}
+	http.ServeContent(w, r, name, info.ModTime(), content)
+}
+
+// openEncoded 按 br > gzip > 原文件的顺序打开，返回文件和对应的 Content-Encoding
+func (h *staticHandler) openEncoded(name, acceptEncoding string) (fs.File, string) {
+	for _, enc := range []struct{ token, suffix string }{{"br", ".br"}, {"gzip", ".gz"}} {
+		if !acceptsEncoding(acceptEncoding, enc.token) {
+			continue
+		}
+		if f, err := h.fsys.Open(name + enc.suffix); err == nil {
+			return f, enc.token
+		}
+	}
+	f, err := h.fsys.Open(name)
+	if err != nil {
+		return nil, ""
+	}
+	return f, ""
+}
+
+// acceptsEncoding 判断 Accept-Encoding 是否接受指定编码（q=0 表示拒绝）
+func acceptsEncoding(header, token string) bool {
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		if !strings.EqualFold(strings.TrimSpace(fields[0]), token) {
+			continue
+		}
+		for _, p := range fields[1:] {
+			p = strings.TrimSpace(p)
+			if strings.HasPrefix(p, "q=") {
+				if q, err := strconv.ParseFloat(p[2:], 64); err == nil && q == 0 {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
+
+var (
+	staticMu      sync.Mutex
+	staticApplied *StaticConfig // 当前生效的静态站点配置
+)
+
+// applyStatic 按配置启动、更新或停止静态站点（启动和热重载时调用）
+func applyStatic(cfg *StaticConfig) error {
+	staticMu.Lock()
+	defer staticMu.Unlock()
+
+	if reflect.DeepEqual(cfg, staticApplied) {
+		return nil
+	}
+	if cfg == nil {
+		localServer.SetRoot(nil)
+		staticApplied = nil
+		return nil
+	}
+
+	var fsys fs.FS
+	var version string
+	if cfg.Dir != "" {
+		dir := config.ResolvePath(cfg.Dir)
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return errors.New(T("err.static_dir", dir))
+		}
+		fsys = os.DirFS(dir)
+	} else if embeddedWeb != nil {
+		fsys = embeddedWeb
+		version = embeddedWebVersion
+	} else {
+		return errors.New(T("err.static_no_assets"))
+	}
+
+	if err := localServer.Start(cfg.Port); err != nil {
+		return err
+	}
+	localServer.SetRoot(newStaticHandler(fsys, *cfg, version))
+	applied := *cfg
+	staticApplied = &applied
+	log.Printf(T("log.static_serving"), localServer.URL(cfg.Path))
+	return nil
+}
+
+// staticURL 返回静态站点地址，未启用时返回空
+func staticURL() string {
+	staticMu.Lock()
+	defer staticMu.Unlock()
+	if staticApplied == nil {
+		return ""
+	}
+	return localServer.URL(staticApplied.Path)
+}
@@ -0,0 +1,49 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"syscall"
+)
+
+// hideWindow 非 Windows 平台无需处理
+func hideWindow(cmd *exec.Cmd) {}
+
+// shellCommand 通过 /bin/sh 执行命令行
+func shellCommand(line string) *exec.Cmd {
+	return exec.Command("/bin/sh", "-c", line)
+}
+
+var shellSafeArg = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// shellQuote 为 /bin/sh 引用一个参数，使空格和特殊字符按原样传给命令
+func shellQuote(arg string) string {
+	if shellSafeArg.MatchString(arg) {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// setProcessGroup 让子进程使用独立进程组，便于连同其子进程一起结束
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// setWindowProcessGroup 让图形程序（如浏览器）使用独立进程组
+func setWindowProcessGroup(cmd *exec.Cmd) {
+	setProcessGroup(cmd)
+}
+
+// terminateProcess 向进程组发送 SIGTERM
+func terminateProcess(p *os.Process) error {
+	return syscall.Kill(-p.Pid, syscall.SIGTERM)
+}
+
+// killProcess 向进程组发送 SIGKILL
+func killProcess(p *os.Process) error {
+	return syscall.Kill(-p.Pid, syscall.SIGKILL)
+}
@@ -0,0 +1,83 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// hideWindow 避免子进程弹出控制台窗口（保留已设置的其他属性）
+func hideWindow(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.HideWindow = true
+	cmd.SysProcAttr.CreationFlags |= 0x08000000 // CREATE_NO_WINDOW
+}
+
+// shellCommand 通过 cmd.exe 执行命令行
+// 命令行原样交给 cmd（/s 只去掉最外层引号），避免按普通参数转义时把其中的引号改写为 \"
+func shellCommand(line string) *exec.Cmd {
+	cmd := exec.Command("cmd", "/s", "/c", line)
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: `cmd /s /c "` + line + `"`}
+	return cmd
+}
+
+// shellQuote 为 cmd.exe 引用一个参数：先按程序解析命令行的规则加引号，
+// 再用 ^ 转义 cmd 的特殊字符（包括引号本身），使 cmd 不解释其中的 & | < > % 等
+func shellQuote(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\"&|<>()^%!,;=") {
+		return arg
+	}
+	quoted := syscall.EscapeArg(arg)
+	if !strings.HasPrefix(quoted, `"`) {
+		quoted = `"` + quoted + `"`
+	}
+	var b strings.Builder
+	for _, r := range quoted {
+		if strings.ContainsRune(`"&|<>()^%!`, r) {
+			b.WriteByte('^')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// setProcessGroup 隐藏窗口并让子进程使用独立进程组
+func setProcessGroup(cmd *exec.Cmd) {
+	hideWindow(cmd)
+	cmd.SysProcAttr.CreationFlags |= 0x00000200 // CREATE_NEW_PROCESS_GROUP
+}
+
+// setWindowProcessGroup 让图形程序（如浏览器）使用独立进程组，不隐藏窗口
+func setWindowProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CreationFlags: 0x00000200, // CREATE_NEW_PROCESS_GROUP
+	}
+}
+
+// terminateProcess 请求进程树退出：有窗口的进程（如浏览器）由不带 /F 的 taskkill 关闭窗口；
+// 以 CREATE_NO_WINDOW 启动的后台进程没有可接收 WM_CLOSE 的窗口，taskkill 会失败，此时直接强制结束，
+// 不必等到 stopTimeout 超时
+func terminateProcess(p *os.Process) error {
+	cmd := exec.Command("taskkill", "/T", "/PID", strconv.Itoa(p.Pid))
+	hideWindow(cmd)
+	if err := cmd.Run(); err != nil {
+		return killProcess(p)
+	}
+	return nil
+}
+
+// killProcess 强制结束进程树
+func killProcess(p *os.Process) error {
+	cmd := exec.Command("taskkill", "/F", "/T", "/PID", strconv.Itoa(p.Pid))
+	hideWindow(cmd)
+	if err := cmd.Run(); err != nil {
+		return p.Kill()
+	}
+	return nil
+}
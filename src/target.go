@@ -0,0 +1,23 @@
+package main
+
+// targetURL 返回实际要打开的地址，按以下顺序确定：
+// 启用静态站点或认证代理时为本地服务地址，启用 SSH 隧道时为隧道的本地地址，匹配网络规则时为规则的地址，
+// 配置了备用地址时为探测选出的地址，否则为当前环境的 url
+func targetURL() string {
+	if u := staticURL(); u != "" {
+		return u
+	}
+	if u := proxyURL(); u != "" {
+		return u
+	}
+	if u := tunnelURL(); u != "" {
+		return u
+	}
+	if u := networkURL(); u != "" {
+		return u
+	}
+	if u := mirrorURL(); u != "" {
+		return u
+	}
+	return config.GetURL()
+}
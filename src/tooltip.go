@@ -0,0 +1,67 @@
+package main
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/energye/systray"
+)
+
+// 托盘提示：第一行为标题，其后为各模块的状态（如等待进度、健康状态）
+
+var (
+	tooltipMu    sync.Mutex
+	tooltipReady bool // 托盘已就绪（非托盘模式下不设置提示）
+	tooltipOrder []string
+	tooltipLines = make(map[string]string)
+)
+
+// setTrayStatus 设置托盘提示中的一行状态，text 为空时移除
+func setTrayStatus(key, text string) {
+	tooltipMu.Lock()
+	if _, ok := tooltipLines[key]; !ok && text != "" {
+		tooltipOrder = append(tooltipOrder, key)
+	}
+	if text == "" {
+		delete(tooltipLines, key)
+		for i, k := range tooltipOrder {
+			if k == key {
+				tooltipOrder = append(tooltipOrder[:i], tooltipOrder[i+1:]...)
+				break
+			}
+		}
+	} else {
+		tooltipLines[key] = text
+	}
+	tooltipMu.Unlock()
+	updateTooltip()
+}
+
+// updateTooltip 按当前标题和状态更新托盘提示
+func updateTooltip() {
+	tooltipMu.Lock()
+	defer tooltipMu.Unlock()
+	if !tooltipReady {
+		return
+	}
+	lines := []string{config.GetTrayTitle()}
+	for _, k := range tooltipOrder {
+		lines = append(lines, tooltipLines[k])
+	}
+	systray.SetTooltip(strings.Join(lines, "\n"))
+}
+
+// trayReady 托盘是否已就绪
+func trayReady() bool {
+	tooltipMu.Lock()
+	defer tooltipMu.Unlock()
+	return tooltipReady
+}
+
+// enableTooltip 托盘就绪后调用，之后的状态变化会更新到托盘提示
+func enableTooltip() {
+	tooltipMu.Lock()
+	tooltipReady = true
+	tooltipMu.Unlock()
+	updateTooltip()
+}
@@ -0,0 +1,473 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// TunnelConfig SSH 隧道：在本地端口转发到 SSH 服务器可访问的地址，网页通过本地端口打开
+type TunnelConfig struct {
+	Host       string     `json:"host"`                 // SSH 服务器，host 或 host:port（默认端口 22）
+	User       string     `json:"user"`                 // 用户名
+	KeyPath    string     `json:"keyPath,omitempty"`    // 私钥路径（相对路径基于配置文件所在目录），空则使用 ssh-agent
+	Passphrase *SecretRef `json:"passphrase,omitempty"` // 私钥密码，从系统凭据存储读取
+	Remote     string     `json:"remote,omitempty"`     // 从 SSH 服务器访问的 host:port，默认为 url 中的地址
+	LocalPort  int        `json:"localPort,omitempty"`  // 本地端口（0 由系统分配）
+	KnownHosts string     `json:"knownHosts,omitempty"` // known_hosts 文件，默认 ~/.ssh/known_hosts
+	HostKey    string     `json:"hostKey,omitempty"`    // 服务器公钥指纹（SHA256:...），指定时不使用 known_hosts
+	Keepalive  Duration   `json:"keepalive,omitempty"`  // 保活间隔，默认 30s
+}
+
+// 隧道状态
+const (
+	tunnelConnecting = "connecting"
+	tunnelConnected  = "connected"
+	tunnelRetrying   = "retrying" // 连接断开或失败，等待重连
+)
+
+// TunnelStatus IPC status 中的隧道状态
+type TunnelStatus struct {
+	State     string    `json:"state"`
+	Local     string    `json:"local"`  // 本地监听地址
+	Remote    string    `json:"remote"` // 转发目标
+	Since     time.Time `json:"since"`
+	Error     string    `json:"error,omitempty"`
+	Reconnect int       `json:"reconnects"`
+}
+
+type sshTunnel struct {
+	cfg    TunnelConfig
+	remote string
+	ln     net.Listener
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu         sync.Mutex
+	client     *ssh.Client
+	ready      chan struct{} // 连接成功后关闭，断开后重新创建
+	state      string
+	since      time.Time
+	err        error
+	reconnects int
+}
+
+var (
+	tunnelApplyMu sync.Mutex // 串行执行 applyTunnel，等待旧隧道关闭时不持有 tunnelMu
+	tunnelMu      sync.Mutex
+	tunnel        *sshTunnel
+)
+
+// applyTunnel 按配置启动、重启或关闭隧道（启动、热重载和切换环境时调用）
+// 未指定 remote 时转发目标来自当前环境的 url，目标变化时同样重建隧道
+func applyTunnel(cfg *TunnelConfig) error {
+	tunnelApplyMu.Lock()
+	defer tunnelApplyMu.Unlock()
+
+	var remote string
+	var remoteErr error
+	if cfg != nil {
+		remote, remoteErr = tunnelRemote(*cfg)
+	}
+
+	tunnelMu.Lock()
+	old := tunnel
+	if old != nil && cfg != nil && remoteErr == nil && reflect.DeepEqual(old.cfg, *cfg) && old.remote == remote {
+		tunnelMu.Unlock()
+		return nil
+	}
+	tunnel = nil
+	tunnelMu.Unlock()
+
+	// 在锁外等待旧隧道退出，避免阻塞状态查询
+	if old != nil {
+		old.Close()
+		setTrayStatus("tunnel", "")
+	}
+	if cfg == nil {
+		return nil
+	}
+	if remoteErr != nil {
+		return remoteErr
+	}
+	t, err := newSSHTunnel(*cfg, remote)
+	if err != nil {
+		return err
+	}
+	tunnelMu.Lock()
+	tunnel = t
+	tunnelMu.Unlock()
+	return nil
+}
+
+// stopTunnel 关闭隧道
+func stopTunnel() {
+	applyTunnel(nil)
+}
+
+// tunnelURL 将当前环境的 url 改写为经隧道访问的本地地址，未启用隧道时返回空
+func tunnelURL() string {
+	tunnelMu.Lock()
+	t := tunnel
+	tunnelMu.Unlock()
+	if t == nil {
+		return ""
+	}
+	u, err := url.Parse(config.GetURL())
+	if err != nil {
+		return ""
+	}
+	u.Host = t.ln.Addr().String()
+	return u.String()
+}
+
+// tunnelRemote 返回转发目标，未指定 remote 时使用当前环境 url 中的地址
+func tunnelRemote(cfg TunnelConfig) (string, error) {
+	if cfg.Remote != "" {
+		return cfg.Remote, nil
+	}
+	u, err := url.Parse(config.GetURL())
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf(T("err.tunnel_remote"), config.GetURL())
+	}
+	return hostPort(u), nil
+}
+
+func newSSHTunnel(cfg TunnelConfig, remote string) (*sshTunnel, error) {
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", cfg.LocalPort))
+	if err != nil {
+		return nil, fmt.Errorf(T("err.tunnel_listen"), err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &sshTunnel{
+		cfg:    cfg,
+		remote: remote,
+		ln:     ln,
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		ready:  make(chan struct{}),
+	}
+	t.setState(tunnelConnecting, nil)
+	log.Printf(T("log.tunnel_listening"), ln.Addr(), cfg.Host, remote)
+	go t.supervise()
+	go t.accept()
+	return t, nil
+}
+
+// Close 关闭监听和 SSH 连接
+func (t *sshTunnel) Close() {
+	t.cancel()
+	t.ln.Close()
+	<-t.done
+}
+
+func (t *sshTunnel) setState(state string, err error) {
+	t.mu.Lock()
+	t.state = state
+	t.since = time.Now()
+	t.err = err
+	t.mu.Unlock()
+
+	text := T("tray.tunnel."+state, t.cfg.Host)
+	if err != nil {
+		text += " (" + err.Error() + ")"
+	}
+	setTrayStatus("tunnel", text)
+}
+
+// Status 返回隧道状态
+func (t *sshTunnel) Status() TunnelStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	st := TunnelStatus{
+		State:     t.state,
+		Local:     t.ln.Addr().String(),
+		Remote:    t.remote,
+		Since:     t.since,
+		Reconnect: t.reconnects,
+	}
+	if t.err != nil {
+		st.Error = t.err.Error()
+	}
+	return st
+}
+
+// supervise 建立 SSH 连接并保活，断开后按退避时间重连
+func (t *sshTunnel) supervise() {
+	defer close(t.done)
+	delay := time.Second
+	for {
+		client, err := t.dial()
+		if err == nil {
+			log.Printf(T("log.tunnel_connected"), t.cfg.Host)
+			t.mu.Lock()
+			t.client = client
+			close(t.ready)
+			t.mu.Unlock()
+			t.setState(tunnelConnected, nil)
+			delay = time.Second
+
+			err = t.keepalive(client)
+
+			t.mu.Lock()
+			t.client = nil
+			t.ready = make(chan struct{})
+			t.mu.Unlock()
+			client.Close()
+		}
+		if t.ctx.Err() != nil {
+			return
+		}
+
+		log.Printf(T("log.tunnel_retry"), t.cfg.Host, err, delay)
+		t.setState(tunnelRetrying, err)
+		select {
+		case <-t.ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		t.mu.Lock()
+		t.reconnects++
+		t.mu.Unlock()
+		if delay *= 2; delay > 30*time.Second {
+			delay = 30 * time.Second
+		}
+	}
+}
+
+// keepalive 定期发送保活请求，失败或隧道关闭时返回
+func (t *sshTunnel) keepalive(client *ssh.Client) error {
+	interval := t.cfg.Keepalive.Or(30 * time.Second)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	closed := make(chan error, 1)
+	go func() { closed <- client.Wait() }()
+	for {
+		select {
+		case <-t.ctx.Done():
+			return t.ctx.Err()
+		case err := <-closed:
+			if err == nil {
+				err = errors.New(T("err.tunnel_closed"))
+			}
+			return err
+		case <-ticker.C:
+			reply := make(chan error, 1)
+			go func() {
+				_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+				reply <- err
+			}()
+			select {
+			case err := <-reply:
+				if err != nil {
+					return err
+				}
+			case <-time.After(interval):
+				return errors.New(T("err.tunnel_keepalive"))
+			}
+		}
+	}
+}
+
+// dial 建立 SSH 连接，隧道关闭时立即中止（包括握手阶段）
+func (t *sshTunnel) dial() (*ssh.Client, error) {
+	auth, closeAuth, err := t.authMethods()
+	if err != nil {
+		return nil, err
+	}
+	// ssh-agent 的签名请求在握手期间经由该连接发送，握手结束后才能关闭
+	defer closeAuth()
+	hostKey, err := t.hostKeyCallback()
+	if err != nil {
+		return nil, err
+	}
+	addr := t.cfg.Host
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "22")
+	}
+
+	const timeout = 15 * time.Second
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(t.ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	handshake := make(chan struct{})
+	defer close(handshake)
+	go func() {
+		select {
+		case <-t.ctx.Done():
+			conn.Close()
+		case <-handshake:
+		}
+	}()
+	conn.SetDeadline(time.Now().Add(timeout))
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, &ssh.ClientConfig{
+		User:            t.cfg.User,
+		Auth:            auth,
+		HostKeyCallback: hostKey,
+	})
+	if err != nil {
+		conn.Close()
+		if t.ctx.Err() != nil {
+			return nil, t.ctx.Err()
+		}
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
+// authMethods 使用私钥文件或 ssh-agent，返回的 closer 在握手结束后调用
+func (t *sshTunnel) authMethods() ([]ssh.AuthMethod, func(), error) {
+	if t.cfg.KeyPath == "" {
+		conn, err := dialSSHAgent()
+		if err != nil {
+			return nil, nil, err
+		}
+		client := agent.NewClient(conn)
+		signers, err := client.Signers()
+		if err != nil {
+			conn.Close()
+			return nil, nil, fmt.Errorf(T("err.ssh_agent_connect"), err)
+		}
+		return []ssh.AuthMethod{ssh.PublicKeys(signers...)}, func() { conn.Close() }, nil
+	}
+
+	data, err := os.ReadFile(config.ResolvePath(expandHome(t.cfg.KeyPath)))
+	if err != nil {
+		return nil, nil, fmt.Errorf(T("err.ssh_key_read"), err)
+	}
+	var signer ssh.Signer
+	if t.cfg.Passphrase != nil {
+		passphrase, err := readSecret(t.cfg.Passphrase.Service, t.cfg.Passphrase.Account)
+		if err != nil {
+			return nil, nil, err
+		}
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(data, []byte(passphrase))
+		if err != nil {
+			return nil, nil, fmt.Errorf(T("err.ssh_key_parse"), err)
+		}
+	} else {
+		signer, err = ssh.ParsePrivateKey(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf(T("err.ssh_key_parse"), err)
+		}
+	}
+	return []ssh.AuthMethod{ssh.PublicKeys(signer)}, func() {}, nil
+}
+
+// hostKeyCallback 校验服务器公钥：优先使用配置的指纹，否则使用 known_hosts
+func (t *sshTunnel) hostKeyCallback() (ssh.HostKeyCallback, error) {
+	if t.cfg.HostKey != "" {
+		want := strings.TrimPrefix(t.cfg.HostKey, "SHA256:")
+		return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			got := strings.TrimPrefix(ssh.FingerprintSHA256(key), "SHA256:")
+			if got != strings.TrimRight(want, "=") {
+				return fmt.Errorf(T("err.ssh_host_key"), hostname, ssh.FingerprintSHA256(key))
+			}
+			return nil
+		}, nil
+	}
+	path := t.cfg.KnownHosts
+	if path == "" {
+		home, _ := os.UserHomeDir()
+		path = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	cb, err := knownhosts.New(config.ResolvePath(expandHome(path)))
+	if err != nil {
+		return nil, fmt.Errorf(T("err.ssh_known_hosts"), err)
+	}
+	return cb, nil
+}
+
+// accept 接受本地连接并通过 SSH 转发
+func (t *sshTunnel) accept() {
+	for {
+		conn, err := t.ln.Accept()
+		if err != nil {
+			return
+		}
+		go t.forward(conn)
+	}
+}
+
+func (t *sshTunnel) forward(local net.Conn) {
+	defer local.Close()
+
+	// 等待 SSH 连接就绪（最多 30s），避免刚启动时浏览器请求直接失败
+	t.mu.Lock()
+	ready := t.ready
+	t.mu.Unlock()
+	select {
+	case <-ready:
+	case <-t.ctx.Done():
+		return
+	case <-time.After(30 * time.Second):
+		return
+	}
+	t.mu.Lock()
+	client := t.client
+	t.mu.Unlock()
+	if client == nil {
+		return
+	}
+
+	remote, err := client.Dial("tcp", t.remote)
+	if err != nil {
+		log.Printf(T("log.tunnel_forward_failed"), t.remote, err)
+		return
+	}
+	defer remote.Close()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(remote, local)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(local, remote)
+		done <- struct{}{}
+	}()
+	<-done
+}
+
+// expandHome 展开路径开头的 ~
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, `~\`) {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
+
+func init() {
+	registerStatus("tunnel", func() interface{} {
+		tunnelMu.Lock()
+		t := tunnel
+		tunnelMu.Unlock()
+		if t == nil {
+			return nil
+		}
+		return t.Status()
+	})
+}
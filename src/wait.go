@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WaitConfig 打开网页前等待目标地址可访问（适合开机自启时网络或远程服务尚未就绪的情况）
+type WaitConfig struct {
+	Method      string   `json:"method,omitempty"`      // 探测方式：head（默认）、get 或 tcp
+	Timeout     Duration `json:"timeout,omitempty"`     // 单次探测超时，默认 5s
+	Interval    Duration `json:"interval,omitempty"`    // 首次重试间隔，之后每次翻倍，默认 1s
+	MaxInterval Duration `json:"maxInterval,omitempty"` // 重试间隔上限，默认 10s
+	MaxWait     Duration `json:"maxWait,omitempty"`     // 最长等待时间，默认 1m
+	OnTimeout   string   `json:"onTimeout,omitempty"`   // 等待超时后：open（默认，仍然打开）或 skip（放弃打开）
+}
+
+var (
+	waitingMu sync.Mutex
+	waiting   = make(map[string]bool) // 正在等待的地址，避免重复点击时并发等待
+)
+
+// waitTarget 按配置等待地址可访问，返回是否应当打开网页
+// 未配置等待、地址为本地服务或同一地址已在等待时立即返回；
+// 配置了离线页面时，地址不可访问会改为打开离线页面
+func waitTarget(target string) bool {
+	return waitTargetContext(context.Background(), target)
+}
+
+// waitTargetContext 同 waitTarget，ctx 取消时立即返回 false（不打开离线页面）
+func waitTargetContext(ctx context.Context, target string) bool {
+	cfg := config.GetWait()
+	offline := config.GetOffline() != nil
+	if cfg == nil {
+		if !offline {
+			return true
+		}
+		// 未配置等待时只做一次快速探测，用于决定是否打开离线页面
+		cfg = &WaitConfig{Timeout: Duration(3 * time.Second), MaxWait: Duration(3 * time.Second)}
+	}
+	// 静态站点由本地服务直接提供，无需等待（认证代理仍需探测，上游不可用时代理返回 502）
+	if staticURL() != "" && strings.HasPrefix(target, localServer.URL("/")) {
+		return true
+	}
+
+	waitingMu.Lock()
+	if waiting[target] {
+		waitingMu.Unlock()
+		log.Printf(T("log.wait_in_progress"), target)
+		return false
+	}
+	waiting[target] = true
+	waitingMu.Unlock()
+	defer func() {
+		waitingMu.Lock()
+		delete(waiting, target)
+		waitingMu.Unlock()
+		setTrayStatus("wait", "")
+	}()
+
+	err := waitURL(ctx, target, *cfg)
+	if err == nil {
+		return true
+	}
+	if ctx.Err() != nil {
+		return false
+	}
+	if offline && openOffline(target) {
+		return false
+	}
+	if cfg.OnTimeout == "skip" {
+		log.Printf(T("log.wait_gave_up"), target, err)
+		return false
+	}
+	log.Printf(T("log.wait_timeout_open"), target, err)
+	return true
+}
+
+// waitURL 按指数退避重试探测，直到成功或超过最长等待时间
+func waitURL(ctx context.Context, target string, cfg WaitConfig) error {
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf(T("err.wait_url"), target)
+	}
+	ctx, cancel := context.WithTimeout(ctx, cfg.MaxWait.Or(time.Minute))
+	defer cancel()
+
+	start := time.Now()
+	interval := cfg.Interval.Or(time.Second)
+	for attempt := 1; ; attempt++ {
+		setTrayStatus("wait", T("tray.waiting", u.Host, attempt, time.Since(start).Round(time.Second)))
+		attemptCtx, attemptCancel := context.WithTimeout(ctx, cfg.Timeout.Or(5*time.Second))
+		err = checkURL(attemptCtx, u, cfg.Method)
+		attemptCancel()
+		if err == nil {
+			if attempt > 1 {
+				log.Printf(T("log.wait_ready"), target, time.Since(start).Round(time.Millisecond))
+			}
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(interval):
+		}
+		interval *= 2
+		if max := cfg.MaxInterval.Or(10 * time.Second); interval > max {
+			interval = max
+		}
+	}
+}
+
+// checkURL 执行一次探测：HTTP 返回非 5xx 状态码或 TCP 端口可连接即视为可访问
+func checkURL(ctx context.Context, u *url.URL, method string) error {
+	switch strings.ToLower(method) {
+	case "tcp":
+		return dialTCP(ctx, hostPort(u))
+	case "get":
+		method = http.MethodGet
+	default:
+		method = http.MethodHead
+	}
+
+	code, err := httpStatus(ctx, method, u.String())
+	if err != nil {
+		// 证书不受信任说明服务已在响应，交给浏览器处理
+		var certErr *tls.CertificateVerificationError
+		if errors.As(err, &certErr) {
+			return nil
+		}
+		return err
+	}
+	if code >= 500 {
+		return fmt.Errorf("HTTP %d", code)
+	}
+	return nil
+}
+
+// hostPort 返回 URL 的 host:port，未指定端口时按协议补全
+func hostPort(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	port := "80"
+	if u.Scheme == "https" {
+		port = "443"
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
@@ -0,0 +1,48 @@
+//go:build webdist
+
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"embed"
+	"io/fs"
+	"log"
+	"path"
+	"strings"
+)
+
+// 构建工具将 WEB_DIST 打包为 assets/web/<内容哈希>.zip，并以 webdist 标签构建
+//
+//go:embed assets/web
+var webArchive embed.FS
+
+func init() {
+	entries, err := webArchive.ReadDir("assets/web")
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if path.Ext(e.Name()) != ".zip" {
+			continue
+		}
+		data, err := webArchive.ReadFile(path.Join("assets/web", e.Name()))
+		if err != nil {
+			log.Printf(T("log.webdist_failed"), err)
+			return
+		}
+		// 直接从内存中的归档读取，不解压到磁盘
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			log.Printf(T("log.webdist_failed"), err)
+			return
+		}
+		if _, err := fs.Stat(zr, "index.html"); err != nil {
+			log.Printf(T("log.webdist_failed"), err)
+			return
+		}
+		embeddedWeb = zr
+		embeddedWebVersion = strings.TrimSuffix(e.Name(), ".zip")
+		return
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WakeOnLanConfig 网络唤醒：打开网页前向目标主机发送魔术包
+type WakeOnLanConfig struct {
+	MAC       string `json:"mac"`                 // 目标主机网卡的 MAC 地址（- 或 : 分隔）
+	Broadcast string `json:"broadcast,omitempty"` // 广播地址，默认 255.255.255.255
+	Port      int    `json:"port,omitempty"`      // UDP 端口，默认 9
+	Repeat    int    `json:"repeat,omitempty"`    // 发送次数，默认 3
+}
+
+// wolInterval 多次发送之间的间隔
+const wolInterval = 100 * time.Millisecond
+
+// magicPacket 生成魔术包：6 个 0xFF 后接 16 次 MAC 地址
+func magicPacket(mac string) ([]byte, error) {
+	hw, err := net.ParseMAC(strings.ReplaceAll(strings.TrimSpace(mac), "-", ":"))
+	if err != nil || len(hw) != 6 {
+		return nil, fmt.Errorf(T("err.wol_mac"), mac)
+	}
+	packet := bytes.Repeat([]byte{0xFF}, 6)
+	packet = append(packet, bytes.Repeat(hw, 16)...)
+	return packet, nil
+}
+
+// sendWakeOnLan 按配置发送魔术包
+func sendWakeOnLan(cfg *WakeOnLanConfig) error {
+	packet, err := magicPacket(cfg.MAC)
+	if err != nil {
+		return err
+	}
+	broadcast := cfg.Broadcast
+	if broadcast == "" {
+		broadcast = "255.255.255.255"
+	}
+	port := cfg.Port
+	if port == 0 {
+		port = 9
+	}
+	repeat := cfg.Repeat
+	if repeat <= 0 {
+		repeat = 3
+	}
+
+	conn, err := net.Dial("udp", net.JoinHostPort(broadcast, strconv.Itoa(port)))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	for i := 0; i < repeat; i++ {
+		if i > 0 {
+			time.Sleep(wolInterval)
+		}
+		if _, err := conn.Write(packet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// wakeHost 已配置网络唤醒时发送魔术包，失败只记录日志（仍继续等待和打开网页）
+func wakeHost() {
+	cfg := config.GetWakeOnLan()
+	if cfg == nil {
+		return
+	}
+	if err := sendWakeOnLan(cfg); err != nil {
+		log.Printf(T("log.wol_failed"), cfg.MAC, err)
+		return
+	}
+	log.Printf(T("log.wol_sent"), cfg.MAC)
+}